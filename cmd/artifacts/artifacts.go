@@ -0,0 +1,189 @@
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return artifacts commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "artifacts",
+		Usage: "Download Synthetics Canary Run artifacts",
+		Flags: append(globalFlags, append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "run",
+				Usage: "Canary run id",
+			},
+			&cli.BoolFlag{
+				Name:    "last",
+				Aliases: []string{"l"},
+				Usage:   "Automatically select last canary run",
+			},
+			&cli.StringFlag{
+				Name:    "out",
+				Aliases: []string{"o"},
+				Usage:   "Local directory where artifacts are downloaded",
+				Value:   "artifacts",
+				EnvVars: []string{"CANARY_ARTIFACTS_DIR"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "type",
+				Aliases: []string{"t"},
+				Usage:   fmt.Sprintf("Download only artifacts of provided type (%s)", strings.Join(canary.ArtifactTypes, ", ")),
+			},
+			&cli.BoolFlag{
+				Name:  "open",
+				Usage: "Open downloaded artifacts directory",
+			},
+		}, config.RunsFilterFlags()...)...),
+		Action:    Action,
+		ArgsUsage: "[path]",
+	}
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Check types filter
+	types := map[string]bool{}
+	for _, artifactType := range c.StringSlice("type") {
+		if isValidType(artifactType) == false {
+			return fmt.Errorf("Artifact type %s not supported, valid types are %s", artifactType, strings.Join(canary.ArtifactTypes, ", "))
+		}
+		types[artifactType] = true
+	}
+
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get canaries
+	canaries, err := config.LoadCanaries(c, clients)
+	if err != nil {
+		return err
+	}
+
+	// Ask canaries selection
+	selectedCanary, err := config.AskSingleCanarySelection(c, *canaries)
+	if err != nil {
+		return err
+	}
+
+	// Check if deployed
+	if selectedCanary.IsDeployed(c.Context) == false {
+		return fmt.Errorf("Canary %s not yet deployed", selectedCanary.Name)
+	}
+
+	// Select run
+	var run *synthetics.CanaryRun
+	if len(c.String("run")) > 0 {
+		run, err = selectedCanary.GetRun(c.Context, c.String("run"))
+		if err != nil {
+			return err
+		}
+	} else {
+		filter, err := config.GetRunsFilter(c)
+		if err != nil {
+			return err
+		}
+
+		// Search only the last matching run, following pages until found
+		if c.Bool("last") {
+			filter.Limit = 1
+			filter.AllPages = true
+		}
+
+		// Retrieve runs
+		runs, err := selectedCanary.ListRuns(c.Context, filter)
+		if err != nil {
+			return err
+		}
+
+		// Check runs
+		if len(runs) == 0 {
+			return errors.New("No runs found for canary")
+		}
+
+		// Ask use to select run
+		if c.Bool("last") {
+			run = runs[0]
+		} else {
+			run, err = config.AskSingleCanaryRun(runs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// List run artifacts
+	artifacts, err := selectedCanary.GetRunArtifacts(c.Context, run)
+	if err != nil {
+		return err
+	}
+
+	// Download artifacts preserving bucket structure
+	count := 0
+	for _, artifact := range artifacts {
+		if len(types) > 0 && types[artifact.Type] == false {
+			continue
+		}
+
+		filePath, err := canary.GetArtifactPath(c.String("out"), artifact.Key)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(fmt.Sprintf("Downloading %s..", artifact.Key))
+		err = selectedCanary.DownloadArtifact(c.Context, artifact, filePath)
+		if err != nil {
+			return err
+		}
+		count++
+	}
+
+	// Check downloaded artifacts
+	if count == 0 {
+		return fmt.Errorf("No artifacts found for run %s", *run.Id)
+	}
+
+	runDir := path.Join(c.String("out"), strings.SplitN(strings.TrimPrefix(*run.ArtifactS3Location, "s3://"), "/", 2)[1])
+	fmt.Println(fmt.Sprintf("%d artifacts saved in %s", count, runDir))
+
+	// Open run directory
+	if c.Bool("open") {
+		return openPath(runDir)
+	}
+
+	return nil
+}
+
+func isValidType(artifactType string) bool {
+	for _, validType := range canary.ArtifactTypes {
+		if artifactType == validType {
+			return true
+		}
+	}
+	return false
+}
+
+func openPath(filePath string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", filePath)
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "start", "", filePath)
+	default:
+		cmd = exec.Command("xdg-open", filePath)
+	}
+	return cmd.Start()
+}
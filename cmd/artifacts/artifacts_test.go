@@ -0,0 +1,89 @@
+package artifacts_test
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/cmd/artifacts"
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/cmd/start"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestMain(m *testing.M) {
+	canary.DefaultPollInterval = 10 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+func deployFailedRun(t *testing.T, backend *fake.Backend, name string) string {
+	backend.CreateBuckets()
+	dir, err := fake.WriteCanary(t.TempDir(), name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", dir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+
+	backend.FailingRuns[name] = "element not found"
+	err = fake.Run(backend, start.NewCommand, "--wait", "--all", dir)
+	if err == nil {
+		t.Fatal("expected run to fail")
+	}
+	return dir
+}
+
+func TestArtifactsLastSearchAllPages(t *testing.T) {
+	backend := fake.NewBackend()
+	dir := deployFailedRun(t, backend, "test-artifacts")
+
+	// Push the failed run after the first page
+	deployed := backend.Canaries["test-artifacts"]
+	for i := 0; i < 150; i++ {
+		deployed.Runs = append([]*synthetics.CanaryRun{{
+			Id:                 aws.String(fmt.Sprintf("passed-%d", i)),
+			ArtifactS3Location: aws.String("No data"),
+			Status:             &synthetics.CanaryRunStatus{State: aws.String(synthetics.CanaryRunStatePassed)},
+			Timeline:           &synthetics.CanaryRunTimeline{Started: deployed.Runs[0].Timeline.Started},
+		}}, deployed.Runs...)
+	}
+
+	out := t.TempDir()
+	err := fake.Run(backend, artifacts.NewCommand, "--last", "--state", "FAILED", "--type", "logs", "--out", out, dir)
+	if err != nil {
+		t.Fatalf("artifacts failed: %s", err)
+	}
+
+	failedRun := deployed.Runs[len(deployed.Runs)-1]
+	logPath := path.Join(out, strings.SplitN(*failedRun.ArtifactS3Location, "/", 2)[1], "log.txt")
+	if _, err := os.Stat(logPath); err != nil {
+		t.Errorf("expected failed run log in %s: %s", logPath, err)
+	}
+}
+
+func TestArtifactsRejectKeysOutsideOutput(t *testing.T) {
+	backend := fake.NewBackend()
+	dir := deployFailedRun(t, backend, "test-traversal")
+
+	// Add an artifact whose key escapes the output directory
+	run := backend.Canaries["test-traversal"].Runs[0]
+	location := strings.SplitN(*run.ArtifactS3Location, "/", 2)
+	backend.Buckets[location[0]][location[1]+"/../../../../../../evil.txt"] = []byte("evil")
+
+	out := path.Join(t.TempDir(), "out")
+	err := fake.Run(backend, artifacts.NewCommand, "--last", "--out", out, dir)
+	if err == nil || strings.Contains(err.Error(), "not a valid path") == false {
+		t.Fatalf("expected artifacts to fail for a key outside output directory, got %v", err)
+	}
+	if _, err := os.Stat(path.Join(path.Dir(out), "evil.txt")); err == nil {
+		t.Error("artifact written outside output directory")
+	}
+}
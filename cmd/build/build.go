@@ -1,13 +1,13 @@
 package build
 
 import (
+	"context"
 	"fmt"
-	"sync"
 
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/daaru00/aws-canary-cli/internal/aws"
 	"github.com/daaru00/aws-canary-cli/internal/canary"
 	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/pool"
 	"github.com/urfave/cli/v2"
 )
 
@@ -17,6 +17,17 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 		Name:  "build",
 		Usage: "Build Synthetics Canary code",
 		Flags: append(globalFlags, []cli.Flag{
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of canaries processed in parallel (0 means no limit)",
+				Value:   5,
+				EnvVars: []string{"CANARY_CONCURRENCY"},
+			},
+			&cli.DurationFlag{
+				Name:    "timeout",
+				Usage:   "Maximum duration of each canary operation, for example 10m (0 means no limit)",
+				EnvVars: []string{"CANARY_OPERATION_TIMEOUT"},
+			},
 			&cli.BoolFlag{
 				Name:    "all",
 				Aliases: []string{"a"},
@@ -35,11 +46,11 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 
 // Action contain the command flow
 func Action(c *cli.Context) error {
-	// Create AWS session
-	ses := aws.NewAwsSession(c)
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
 
 	// Get canaries
-	canaries, err := config.LoadCanaries(c, ses)
+	canaries, err := config.LoadCanaries(c, clients)
 	if err != nil {
 		return err
 	}
@@ -50,45 +61,21 @@ func Action(c *cli.Context) error {
 		return err
 	}
 
-	// Setup wait group for async jobs
-	var waitGroup sync.WaitGroup
-
-	// Setup deploy chan error
-	errs := make(chan error, len(*canaries))
-
-	// Loop over found canaries
-	for _, cy := range *canaries {
-
-		// Execute parallel deploy
-		waitGroup.Add(1)
-		go func(canary *canary.Canary) {
-			defer waitGroup.Done()
-			output, err := SingleCanary(ses, canary)
-
-			// Check output flag
-			if c.Bool("output") && len(*output) > 0 {
-				fmt.Println(fmt.Sprintf("[%s] Output: \n%s", canary.Name, *output))
-			}
+	// Execute parallel build
+	summary := pool.New(c.Int("concurrency"), c.Duration("timeout")).Run(c.Context, *canaries, func(ctx context.Context, canary *canary.Canary) error {
+		output, err := SingleCanary(ctx, clients, canary)
 
-			errs <- err
-		}(cy)
-	}
-
-	// Wait until all remove ends
-	waitGroup.Wait()
+		// Check output flag
+		if c.Bool("output") && len(*output) > 0 {
+			canary.Logf("Output: \n%s", *output)
+		}
 
-	// Close errors channel
-	close(errs)
+		return err
+	})
+	summary.Print()
 
 	// Check errors
-	var inError int
-	for i := 0; i < len(*canaries); i++ {
-		err := <-errs
-		if err != nil {
-			inError++
-			fmt.Println(err)
-		}
-	}
+	inError := summary.Count(pool.StatusFailed, pool.StatusInterrupted)
 	if inError > 0 {
 		return fmt.Errorf("%d of %d canaries fail build", inError, len(*canaries))
 	}
@@ -97,22 +84,22 @@ func Action(c *cli.Context) error {
 }
 
 // SingleCanary build single canary code
-func SingleCanary(ses *session.Session, canary *canary.Canary) (*string, error) {
+func SingleCanary(ctx context.Context, clients *aws.Clients, canary *canary.Canary) (*string, error) {
 	var err error
 	var output string
 
 	// Install code dependencies
 	if canary.IsPythonRuntime() {
-		fmt.Println(fmt.Sprintf("[%s] Installing pip dependencies..", canary.Name))
-		output, err = canary.Code.InstallPipDependencies()
+		canary.Logf("Installing pip dependencies..")
+		output, err = canary.Code.InstallPipDependencies(ctx)
 	} else if canary.IsNodeRuntime() {
-		fmt.Println(fmt.Sprintf("[%s] Installing npm dependencies..", canary.Name))
-		output, err = canary.Code.InstallNpmDependencies()
+		canary.Logf("Installing npm dependencies..")
+		output, err = canary.Code.InstallNpmDependencies(ctx)
 	}
 	if err != nil {
 		return &output, err
 	}
 
-	fmt.Println(fmt.Sprintf("[%s] Dependencies installed!", canary.Name))
+	canary.Logf("Dependencies installed!")
 	return &output, nil
 }
@@ -0,0 +1,231 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/daaru00/aws-canary-cli/internal/alarm"
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/dashboard"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return dashboard commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "dashboard",
+		Usage: "Deploy a CloudWatch Dashboard for Synthetics Canaries",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.StringFlag{
+				Name:    "name",
+				Aliases: []string{"n"},
+				Usage:   "Dashboard name, by default the dashboard name declared in canaries configuration is used",
+			},
+			&cli.StringFlag{
+				Name:    "template",
+				Aliases: []string{"t"},
+				Usage:   fmt.Sprintf("Dashboard layout template (%s)", strings.Join(dashboard.Templates, ", ")),
+			},
+			&cli.BoolFlag{
+				Name:  "print",
+				Usage: "Print dashboard body instead of deploying it",
+			},
+			&cli.BoolFlag{
+				Name:    "all",
+				Aliases: []string{"a"},
+				Usage:   "Select all canaries",
+			},
+		}...),
+		Action:    Action,
+		ArgsUsage: "[path...]",
+	}
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get caller infos
+	accountID := aws.GetCallerAccountID(clients)
+	region := aws.GetCallerRegion(clients)
+	if accountID == nil {
+		return errors.New("No valid AWS credentials found")
+	}
+
+	// Get canaries
+	canaries, err := config.LoadCanaries(c, clients)
+	if err != nil {
+		return err
+	}
+
+	// Ask canaries selection
+	canaries, err = config.AskMultipleCanariesSelection(c, *canaries)
+	if err != nil {
+		return err
+	}
+
+	// Override dashboard configuration
+	for _, selectedCanary := range *canaries {
+		if len(c.String("name")) > 0 {
+			selectedCanary.Dashboard.Name = c.String("name")
+		}
+		if len(c.String("template")) > 0 {
+			selectedCanary.Dashboard.Template = c.String("template")
+		}
+		if len(selectedCanary.Dashboard.Name) == 0 {
+			return fmt.Errorf("Canary %s has no dashboard name, use --name flag or set dashboard name in configuration", selectedCanary.Name)
+		}
+	}
+
+	// Group canaries by dashboard
+	configs, canaryNames, err := GroupCanaries(*canaries)
+	if err != nil {
+		return err
+	}
+
+	// Deploy dashboards, selected canaries replace the ones already shown
+	for _, dashboardConfig := range configs {
+		if c.Bool("print") {
+			board := dashboard.New(clients, &dashboardConfig.Name)
+			board.Template = dashboardConfig.Template
+			body, err := Build(c.Context, clients, region, accountID, board, canaryNames[dashboardConfig.Name])
+			if err != nil {
+				return err
+			}
+			fmt.Println(body)
+			continue
+		}
+
+		fmt.Println(fmt.Sprintf("Deploying dashboard %s..", dashboardConfig.Name))
+		err = Sync(c.Context, clients, region, accountID, dashboardConfig, canaryNames[dashboardConfig.Name])
+		if err != nil {
+			return err
+		}
+		fmt.Println(fmt.Sprintf("Dashboard %s deployed with %d canaries", dashboardConfig.Name, len(canaryNames[dashboardConfig.Name])))
+	}
+
+	return nil
+}
+
+// GroupCanaries return dashboards configuration and canaries names by dashboard name,
+// canaries without a dashboard name are ignored
+func GroupCanaries(canaries []*canary.Canary) ([]dashboard.Config, map[string][]string, error) {
+	configs := []dashboard.Config{}
+	canaryNames := map[string][]string{}
+
+	for _, canary := range canaries {
+		dashboardConfig := canary.Dashboard
+		if len(dashboardConfig.Name) == 0 {
+			continue
+		}
+
+		err := dashboardConfig.Validate()
+		if err != nil {
+			return configs, canaryNames, fmt.Errorf("[%s] Error: %s", canary.Name, err)
+		}
+
+		if _, ok := canaryNames[dashboardConfig.Name]; !ok {
+			configs = append(configs, dashboardConfig)
+		}
+		canaryNames[dashboardConfig.Name] = append(canaryNames[dashboardConfig.Name], canary.Name)
+	}
+
+	// First canary that declare a template set it for the whole dashboard
+	for i, dashboardConfig := range configs {
+		for _, canary := range canaries {
+			if canary.Dashboard.Name == dashboardConfig.Name && len(canary.Dashboard.Template) > 0 {
+				configs[i].Template = canary.Dashboard.Template
+				break
+			}
+		}
+	}
+
+	return configs, canaryNames, nil
+}
+
+// Build return dashboard body for provided canaries
+func Build(ctx context.Context, clients *aws.Clients, region *string, accountID *string, board *dashboard.Dashboard, canaryNames []string) (string, error) {
+	members := []*dashboard.Member{}
+
+	sort.Strings(canaryNames)
+	for _, canaryName := range canaryNames {
+		member := &dashboard.Member{
+			Canary: canaryName,
+		}
+
+		// Log group is available only for deployed canaries
+		logGroupName, err := canary.New(clients, canaryName).GetLogGroupName(ctx)
+		if err == nil {
+			member.LogGroup = logGroupName
+		}
+
+		// Search canary alarms
+		alarmNames, err := alarm.ListNames(ctx, clients, fmt.Sprintf("CloudWatchSyntheticsAlarm-%s-%s-", *region, canaryName), canaryName)
+		if err != nil {
+			return "", err
+		}
+		for _, alarmName := range alarmNames {
+			member.Alarms = append(member.Alarms, fmt.Sprintf("arn:aws:cloudwatch:%s:%s:alarm:%s", *region, *accountID, alarmName))
+		}
+
+		members = append(members, member)
+	}
+
+	return board.Build(*region, members)
+}
+
+// Sync deploy dashboard with provided canaries, dashboard is removed when no canaries are left
+func Sync(ctx context.Context, clients *aws.Clients, region *string, accountID *string, dashboardConfig dashboard.Config, canaryNames []string) error {
+	board := dashboard.New(clients, &dashboardConfig.Name)
+	if len(dashboardConfig.Template) > 0 {
+		board.Template = dashboardConfig.Template
+	}
+
+	// Remove dashboard without canaries
+	if len(canaryNames) == 0 {
+		if board.IsDeployed(ctx) == false {
+			return nil
+		}
+		return board.Remove(ctx)
+	}
+
+	// Build and deploy dashboard
+	body, err := Build(ctx, clients, region, accountID, board, canaryNames)
+	if err != nil {
+		return err
+	}
+
+	return board.Deploy(ctx, body)
+}
+
+// Update add and remove canaries from the ones already shown in dashboard
+func Update(ctx context.Context, clients *aws.Clients, region *string, accountID *string, dashboardConfig dashboard.Config, added []string, removed []string) error {
+	canaryNames, err := dashboard.New(clients, &dashboardConfig.Name).GetCanaryNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Merge canaries names
+	selected := map[string]bool{}
+	for _, canaryName := range canaryNames {
+		selected[canaryName] = true
+	}
+	for _, canaryName := range added {
+		selected[canaryName] = true
+	}
+	for _, canaryName := range removed {
+		delete(selected, canaryName)
+	}
+	canaryNames = []string{}
+	for canaryName := range selected {
+		canaryNames = append(canaryNames, canaryName)
+	}
+
+	return Sync(ctx, clients, region, accountID, dashboardConfig, canaryNames)
+}
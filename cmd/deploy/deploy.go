@@ -1,21 +1,25 @@
 package deploy
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/synthetics"
 	"github.com/daaru00/aws-canary-cli/cmd/build"
+	"github.com/daaru00/aws-canary-cli/cmd/dashboard"
 	"github.com/daaru00/aws-canary-cli/cmd/start"
+	"github.com/daaru00/aws-canary-cli/internal/alarm"
 	"github.com/daaru00/aws-canary-cli/internal/aws"
 	"github.com/daaru00/aws-canary-cli/internal/bucket"
 	"github.com/daaru00/aws-canary-cli/internal/canary"
 	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/group"
 	"github.com/daaru00/aws-canary-cli/internal/iam"
+	"github.com/daaru00/aws-canary-cli/internal/pool"
+	"github.com/daaru00/aws-canary-cli/internal/topic"
 	"github.com/urfave/cli/v2"
 )
 
@@ -56,6 +60,26 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 				Aliases: []string{"s"},
 				Usage:   "Start canary after deploy",
 			},
+			&cli.BoolFlag{
+				Name:    "dashboard",
+				Aliases: []string{"d"},
+				Usage:   "Update canaries dashboards declared in configuration",
+			},
+			&cli.BoolFlag{
+				Name:  "safe",
+				Usage: "Update deployed canaries only when a dry run with new code and configuration passes",
+			},
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of canaries processed in parallel (0 means no limit)",
+				Value:   5,
+				EnvVars: []string{"CANARY_CONCURRENCY"},
+			},
+			&cli.DurationFlag{
+				Name:    "timeout",
+				Usage:   "Maximum duration of each canary operation, for example 10m (0 means no limit)",
+				EnvVars: []string{"CANARY_OPERATION_TIMEOUT"},
+			},
 			&cli.BoolFlag{
 				Name:    "all",
 				Aliases: []string{"a"},
@@ -69,12 +93,12 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 
 // Action contain the command flow
 func Action(c *cli.Context) error {
-	// Create AWS session
-	ses := aws.NewAwsSession(c)
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
 
 	// Get caller infos
-	accountID := aws.GetCallerAccountID(ses)
-	region := aws.GetCallerRegion(ses)
+	accountID := aws.GetCallerAccountID(clients)
+	region := aws.GetCallerRegion(clients)
 	if accountID == nil {
 		return errors.New("No valid AWS credentials found")
 	}
@@ -84,17 +108,17 @@ func Action(c *cli.Context) error {
 	if len(artifactBucketName) == 0 {
 		artifactBucketName = fmt.Sprintf("cw-syn-results-%s-%s", *accountID, *region)
 	}
-	artifactBucket, err := deployBucket(ses, &artifactBucketName)
+	artifactBucket, err := deployBucket(c.Context, clients, &artifactBucketName, c.String("project"))
 	if err != nil {
 		return err
 	}
-	err = artifactBucket.DeployLifecycleConfigurationExpires(90)
+	err = artifactBucket.DeployLifecycleConfigurationExpires(c.Context, 90)
 	if err != nil {
 		return err
 	}
 
 	// Get canaries
-	canaries, err := config.LoadCanaries(c, ses)
+	canaries, err := config.LoadCanaries(c, clients)
 	if err != nil {
 		return err
 	}
@@ -112,71 +136,110 @@ func Action(c *cli.Context) error {
 		if len(sourceBucketName) == 0 {
 			sourceBucketName = fmt.Sprintf("cw-syn-sources-%s-%s", *accountID, *region)
 		}
-		sourceBucket, err = deployBucket(ses, &sourceBucketName)
+		sourceBucket, err = deployBucket(c.Context, clients, &sourceBucketName, c.String("project"))
 		if err != nil {
 			return err
 		}
 	}
 
-	// Setup wait group for async jobs
-	var waitGroup sync.WaitGroup
+	// Get runtimes, used only to warn about deprecated ones
+	runtimes, _ := canary.ListRuntimes(c.Context, clients)
 
-	// Setup errors channel
-	errs := make(chan error, len(*canaries))
+	// Setup retry policy
+	retryPolicy := aws.NewRetryPolicy(c)
 
-	// Loop over found canaries
-	for _, cy := range *canaries {
+	// Execute parallel deploy
+	summary := pool.New(c.Int("concurrency"), c.Duration("timeout")).Run(c.Context, *canaries, func(ctx context.Context, canary *canary.Canary) error {
+		var err error
 
-		// Execute parallel deploy
-		waitGroup.Add(1)
-		go func(canary *canary.Canary) {
-			var err error
-			defer waitGroup.Done()
+		if c.Bool("build") {
+			_, err = build.SingleCanary(ctx, clients, canary)
+		}
 
-			if err == nil && c.Bool("build") {
-				_, err = build.SingleCanary(ses, canary)
-			}
+		if err == nil {
+			warnDeprecatedRuntime(canary, runtimes)
+			err = SingleCanary(ctx, clients, retryPolicy, region, accountID, canary, artifactBucket, sourceBucket, nil, c.Bool("safe"))
+		}
 
-			if err == nil {
-				err = deploySingleCanary(ses, region, accountID, canary, artifactBucket, sourceBucket)
+		if err == nil && c.Bool("start") {
+			err = start.SingleCanary(ctx, canary, false)
+			if pool.IsSkipped(err) {
+				err = nil
 			}
+		}
 
-			if err == nil && c.Bool("start") {
-				err = start.SingleCanary(canary)
-			}
+		return err
+	})
+	summary.Print()
 
-			errs <- err
-		}(cy)
+	// Update dashboards
+	if c.Bool("dashboard") {
+		err = deployDashboards(c.Context, clients, region, accountID, *canaries)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Wait until all remove ends
-	waitGroup.Wait()
+	// Check errors
+	inError := summary.Count(pool.StatusFailed, pool.StatusInterrupted)
+	if inError > 0 {
+		return fmt.Errorf("%d of %d canaries fail deploy", inError, len(*canaries))
+	}
 
-	// Close errors channel
-	close(errs)
+	return nil
+}
 
-	// Check errors
-	var inError int
-	for i := 0; i < len(*canaries); i++ {
-		err := <-errs
-		if err != nil {
-			inError++
-			fmt.Println(err)
+func warnDeprecatedRuntime(selectedCanary *canary.Canary, runtimes []*canary.Runtime) {
+	runtime := canary.FindRuntime(runtimes, selectedCanary.RuntimeVersion)
+	if runtime == nil || runtime.DeprecationDate == nil {
+		return
+	}
+
+	// Suggest latest runtime of the same family
+	suggestion := ""
+	if latest := canary.FindLatestRuntime(runtimes, runtime.GetFamily()); latest != nil && latest.Name != runtime.Name {
+		suggestion = fmt.Sprintf(", use upgrade-runtime command to upgrade it to %s", latest.Name)
+	}
+
+	deprecationDate := runtime.DeprecationDate.Local().Format("2006-01-02")
+	if runtime.IsDeprecated() {
+		selectedCanary.Logf("Warning: runtime %s is deprecated since %s%s", runtime.Name, deprecationDate, suggestion)
+	} else {
+		selectedCanary.Logf("Warning: runtime %s will be deprecated on %s%s", runtime.Name, deprecationDate, suggestion)
+	}
+}
+
+func deployDashboards(ctx context.Context, clients *aws.Clients, region *string, accountID *string, canaries []*canary.Canary) error {
+	// Add only deployed canaries
+	deployed := []*canary.Canary{}
+	for _, canary := range canaries {
+		if canary.IsDeployed(ctx) {
+			deployed = append(deployed, canary)
 		}
 	}
-	if inError > 0 {
-		return fmt.Errorf("%d of %d canaries fail deploy", inError, len(*canaries))
+
+	configs, canaryNames, err := dashboard.GroupCanaries(deployed)
+	if err != nil {
+		return err
+	}
+	for _, config := range configs {
+		fmt.Println(fmt.Sprintf("Deploying dashboard %s..", config.Name))
+		err = dashboard.Update(ctx, clients, region, accountID, config, canaryNames[config.Name], nil)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func deployBucket(ses *session.Session, bucketName *string) (*bucket.Bucket, error) {
+func deployBucket(ctx context.Context, clients *aws.Clients, bucketName *string, project string) (*bucket.Bucket, error) {
 	fmt.Println(fmt.Sprintf("Checking bucket %s..", *bucketName))
 
-	// Check bucket
-	bucket := bucket.New(ses, bucketName)
-	if bucket.IsDeployed() == false {
+	// Check bucket, it is shared between canaries so only the project is tagged
+	bucket := bucket.New(clients, bucketName)
+	bucket.Tags = aws.GetManagedTags(project, "")
+	if bucket.IsDeployed(ctx) == false {
 		// Ask for deploy
 		confirm := false
 		prompt := &survey.Confirm{
@@ -192,7 +255,7 @@ func deployBucket(ses *session.Session, bucketName *string) (*bucket.Bucket, err
 
 	// Deploy bucket
 	fmt.Println(fmt.Sprintf("Deploying bucket %s..", *bucketName))
-	err := bucket.Deploy()
+	err := bucket.Deploy(ctx)
 	if err != nil {
 		return bucket, err
 	}
@@ -200,13 +263,14 @@ func deployBucket(ses *session.Session, bucketName *string) (*bucket.Bucket, err
 	return bucket, nil
 }
 
-func deployIamRole(ses *session.Session, roleName *string, policy *iam.Policy) (*iam.Role, error) {
+func deployIamRole(ctx context.Context, clients *aws.Clients, roleName *string, policy *iam.Policy, tags map[string]string) (*iam.Role, error) {
 	// Prepare role
-	role := iam.NewRole(ses, roleName)
+	role := iam.NewRole(clients, roleName)
 	role.SetInlinePolicy(policy)
+	role.Tags = tags
 
 	// Deploy role
-	err := role.Deploy()
+	err := role.Deploy(ctx)
 	if err != nil {
 		return role, err
 	}
@@ -214,10 +278,14 @@ func deployIamRole(ses *session.Session, roleName *string, policy *iam.Policy) (
 	return role, nil
 }
 
-func buildIamPolicy(ses *session.Session, policyName *string, artifactBucket *bucket.Bucket, policyStatements *[]iam.StatementEntry, region *string, accountID *string) (*iam.Policy, error) {
+// BuildIamPolicy build the inline policy of canary execution role
+func BuildIamPolicy(clients *aws.Clients, policyName *string, artifactBucket *bucket.Bucket, artifactKmsKey *string, policyStatements *[]iam.StatementEntry, region *string, accountID *string) (*iam.Policy, error) {
 	// Build policy
-	policy := iam.NewPolicy(ses, policyName)
+	policy := iam.NewPolicy(clients, policyName)
 	policy.AddArtifactBucketPermission(artifactBucket)
+	if len(*artifactKmsKey) > 0 {
+		policy.AddKMSPermission(artifactKmsKey)
+	}
 	policy.AddLogPermission(region, accountID)
 	policy.AddMetricsPermission()
 	policy.AddSSMParamersPermission(region, accountID)
@@ -233,111 +301,372 @@ func buildIamPolicy(ses *session.Session, policyName *string, artifactBucket *bu
 	return policy, nil
 }
 
-func deploySingleCanary(ses *session.Session, region *string, accountID *string, canary *canary.Canary, artifactBucket *bucket.Bucket, sourceBucket *bucket.Bucket) error {
+// SingleCanary deploy a single canary, when a version is provided its archive, already present
+// in source bucket, is deployed instead of canary code. Versions uploaded to source bucket are
+// recorded in canary deploy history. When safe is true updates are applied only if a dry run passes.
+func SingleCanary(ctx context.Context, clients *aws.Clients, retryPolicy *aws.RetryPolicy, region *string, accountID *string, canary *canary.Canary, artifactBucket *bucket.Bucket, sourceBucket *bucket.Bucket, version *canary.Version, safe bool) error {
 	var err error
 	var role *iam.Role
 
+	// Check alarms configuration before deploying anything
+	err = validateAlarms(canary)
+	if err != nil {
+		return err
+	}
+	if canary.Notifications != nil {
+		err = canary.Notifications.Validate()
+		if err != nil {
+			return fmt.Errorf("[%s] Error: %s", canary.Name, err)
+		}
+	}
+	err = canary.Dashboard.Validate()
+	if err != nil {
+		return fmt.Errorf("[%s] Error: %s", canary.Name, err)
+	}
+	err = canary.ArtifactConfig.Validate()
+	if err != nil {
+		return fmt.Errorf("[%s] Error: %s", canary.Name, err)
+	}
+
+	// Get deploying user, recorded in history
+	user := ""
+	if version != nil || sourceBucket != nil {
+		user, err = aws.GetCallerArn(clients)
+		if err != nil {
+			return fmt.Errorf("[%s] Error: cannot get caller identity: %s", canary.Name, err)
+		}
+	}
+
+	if version == nil {
+		// Elaborate path prefix
+		codePathPrefix := canary.GetCodePathPrefix()
+
+		// Prepare canary code
+		canary.Logf("Preparing code..")
+		err = canary.Code.CreateArchive(ctx, &canary.Name, &codePathPrefix)
+		if err != nil {
+			return err
+		}
+
+		// Clean archive at the end of deploy
+		defer cleanTemporaryResources(canary)
+
+		// Upload canary code
+		if sourceBucket != nil {
+			version, err = canary.NewVersion(ctx, region)
+			if err != nil {
+				return err
+			}
+
+			canary.Logf("Uploading code version %s..", version.ID)
+			err = canary.Code.Upload(ctx, sourceBucket, &version.Archive)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// Use already uploaded archive
+		canary.Code.SetS3Location(*sourceBucket.Name, version.Archive)
+	}
+
+	isAlreadyDeployed := canary.IsDeployed(ctx)
+	artifactBucketLocation := *artifactBucket.Location + "/canary/" + canary.Name
+
+	// Test update with a dry run, deployed canary, role and policy are left untouched when it fails
+	if safe && isAlreadyDeployed {
+		canary.Logf("Starting dry run..")
+		run, err := canary.DryRun(ctx, &artifactBucketLocation)
+		if err != nil {
+			return err
+		}
+		if *run.Status.State != synthetics.CanaryRunStatePassed {
+			return fmt.Errorf("[%s] Dry run failed, canary not updated: %s", canary.Name, canary.GetDryRunFailure(ctx, run))
+		}
+		canary.Logf("Dry run passed")
+	}
+
 	// Check provided role
 	if len(canary.RoleName) > 0 {
-		role = iam.NewRole(ses, &canary.RoleName)
+		role = iam.NewRole(clients, &canary.RoleName)
 	} else {
 
 		// Deploy iam policy
-		fmt.Println(fmt.Sprintf("[%s] Build policy..", canary.Name))
+		canary.Logf("Build policy..")
 		policyName := fmt.Sprintf("CloudWatchSyntheticsPolicy-%s-%s", *region, canary.Name)
-		policy, err := buildIamPolicy(ses, &policyName, artifactBucket, &canary.PolicyStatements, region, accountID)
+		artifactKmsKey := canary.ArtifactConfig.GetKmsKey()
+		policy, err := BuildIamPolicy(clients, &policyName, artifactBucket, &artifactKmsKey, &canary.PolicyStatements, region, accountID)
 		if err != nil {
 			return err
 		}
 
 		// Deploy iam role
-		fmt.Println(fmt.Sprintf("[%s] Deploying role..", canary.Name))
+		canary.Logf("Deploying role..")
 		roleName := fmt.Sprintf("CloudWatchSyntheticsRole-%s-%s", *region, canary.Name)
-		role, err = deployIamRole(ses, &roleName, policy)
+		role, err = deployIamRole(ctx, clients, &roleName, policy, canary.GetManagedTags())
 		if err != nil {
 			return err
 		}
 	}
 
-	// Elaborate path prefix
-	codePathPrefix := ""
-	if canary.IsPythonRuntime() {
-		codePathPrefix = "python"
-	} else if canary.IsNodeRuntime() {
-		codePathPrefix = "nodejs/node_modules"
+	// Deploy canary
+	if !isAlreadyDeployed {
+		canary.Logf("Creating..")
+	} else {
+		canary.Logf("Updating..")
 	}
 
-	// Prepare canary code
-	fmt.Println(fmt.Sprintf("[%s] Preparing code..", canary.Name))
-	err = canary.Code.CreateArchive(&canary.Name, &codePathPrefix)
+	// A newly created role can take a while before Lambda is able to assume it,
+	// in that case the canary ends in error state and the deploy is retried
+	var status *synthetics.CanaryStatus
+	err = retryPolicy.Do(ctx, func() error {
+		err := canary.Deploy(ctx, role, &artifactBucketLocation)
+		if err != nil {
+			return err
+		}
+
+		// Wait until canary is created
+		canary.Logf("Waiting..")
+		status, err = canary.WaitStatus(ctx, func(status *synthetics.CanaryStatus) bool {
+			return *status.State != "CREATING" && *status.State != "UPDATING"
+		})
+		if err != nil {
+			return err
+		}
+
+		// Check if role was not ready
+		if *status.State == "ERROR" && aws.IsRoleNotAssumableError(*status.StateReason) {
+			canary.Logf("Role not yet assumable, retrying..")
+			return &aws.RoleNotAssumableError{Reason: *status.StateReason}
+		}
+
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	// Clean archive at the end of deploy
-	defer cleanTemporaryResources(canary)
+	// Update tags
+	if isAlreadyDeployed {
+		canary.Logf("Updating tags..")
+		err = canary.UpdateTags(ctx, region, accountID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check for deploy error
+	if *status.State == "ERROR" {
+		return fmt.Errorf("[%s] Error: %s", canary.Name, *status.StateReason)
+	}
 
-	// Upload canary code
-	if sourceBucket != nil {
-		fmt.Println(fmt.Sprintf("[%s] Uploading code..", canary.Name))
-		err = canary.Code.Upload(sourceBucket, region)
+	// Record deployed version
+	if version != nil {
+		version.User = user
+		version.Date = time.Now()
+		err = canary.AddHistory(ctx, sourceBucket, region, version)
 		if err != nil {
 			return err
 		}
 	}
 
-	isAlreadyDeployed := canary.IsDeployed()
+	// Deploy notifications topic
+	topicArn, err := deployTopic(ctx, clients, region, accountID, canary)
+	if err != nil {
+		return err
+	}
 
-	// Deploy canary
-	if !isAlreadyDeployed {
-		fmt.Println(fmt.Sprintf("[%s] Creating..", canary.Name))
-	} else {
-		fmt.Println(fmt.Sprintf("[%s] Updating..", canary.Name))
+	// Reconcile alarms
+	err = deployAlarms(ctx, clients, region, canary, topicArn)
+	if err != nil {
+		return err
 	}
-	artifactBucketLocation := *artifactBucket.Location + "/canary/" + canary.Name
-	err = canary.Deploy(role, &artifactBucketLocation)
+
+	// Reconcile groups
+	err = deployGroups(ctx, clients, region, accountID, canary)
 	if err != nil {
 		return err
 	}
 
-	// Update tags
-	if isAlreadyDeployed {
-		fmt.Println(fmt.Sprintf("[%s] Updating tags..", canary.Name))
-		err = canary.UpdateTags(region, accountID)
+	canary.Logf("Deploy completed!")
+	return nil
+}
+
+func validateAlarms(canary *canary.Canary) error {
+	names := map[string]bool{}
+	for _, config := range canary.Alarms {
+		err := config.Validate()
 		if err != nil {
-			return err
+			return fmt.Errorf("[%s] Error: %s", canary.Name, err)
 		}
+		if names[config.GetName()] {
+			return fmt.Errorf("[%s] Error: alarm name %s already used, set a different name", canary.Name, config.GetName())
+		}
+		names[config.GetName()] = true
 	}
+	return nil
+}
 
-	// Wait until canary is created
-	var status *synthetics.CanaryStatus
-	fmt.Println(fmt.Sprintf("[%s] Waiting..", canary.Name))
-	for {
-		time.Sleep(1000 * time.Millisecond)
+func deployTopic(ctx context.Context, clients *aws.Clients, region *string, accountID *string, canary *canary.Canary) (*string, error) {
+	canaryTopicName := fmt.Sprintf("CloudWatchSyntheticsTopic-%s-%s", *region, canary.Name)
+	canaryTopic := topic.New(clients, &canaryTopicName, region, accountID)
+	canaryTopic.Tags = canary.GetManagedTags()
+
+	// Remove canary topic when notifications are disabled or moved to project topic
+	if canary.Notifications == nil || len(canary.Notifications.Project) > 0 {
+		if canaryTopic.IsDeployed(ctx) {
+			canary.Logf("Removing topic..")
+			err := canaryTopic.Remove(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if canary.Notifications == nil {
+			return nil, nil
+		}
+	}
 
-		// Get canary status
-		status, err = canary.GetStatus()
+	// Project topic is shared, subscriptions declared by other canaries are preserved
+	notificationsTopic := canaryTopic
+	exclusive := true
+	if len(canary.Notifications.Project) > 0 {
+		projectTopicName := fmt.Sprintf("CloudWatchSyntheticsProjectTopic-%s-%s", *region, canary.Notifications.Project)
+		notificationsTopic = topic.New(clients, &projectTopicName, region, accountID)
+		notificationsTopic.Tags = aws.GetManagedTags(canary.Project, "")
+		exclusive = false
+	}
+	notificationsTopic.KmsKey = canary.Notifications.KmsKey
+
+	// Deploy topic
+	canary.Logf("Deploying topic %s..", *notificationsTopic.Name)
+	err := notificationsTopic.Deploy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deploy subscriptions
+	err = notificationsTopic.DeploySubscriptions(ctx, canary.Notifications.Subscriptions, exclusive)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationsTopic.Arn, nil
+}
+
+func deployAlarms(ctx context.Context, clients *aws.Clients, region *string, canary *canary.Canary, topicArn *string) error {
+	alarmPrefix := fmt.Sprintf("CloudWatchSyntheticsAlarm-%s-%s-", *region, canary.Name)
+
+	// Deploy alarms
+	if len(canary.Alarms) > 0 {
+		canary.Logf("Deploying alarms..")
+	}
+	desired := map[string]bool{}
+	for _, config := range canary.Alarms {
+		alarmName := alarmPrefix + config.GetName()
+
+		// Notify alarm and recovery to managed topic
+		if topicArn != nil {
+			config.Actions.Alarm = appendAction(config.Actions.Alarm, *topicArn)
+			config.Actions.OK = appendAction(config.Actions.OK, *topicArn)
+		}
+
+		canaryAlarm := alarm.New(clients, &alarmName, config)
+		canaryAlarm.Tags = canary.GetManagedTags()
+		err := canaryAlarm.Deploy(ctx, canary.Name)
 		if err != nil {
 			return err
 		}
+		desired[alarmName] = true
+	}
 
-		// Check canary state
-		if *status.State != "CREATING" && *status.State != "UPDATING" {
-			break
+	// Remove alarms no longer in configuration
+	names, err := alarm.ListNames(ctx, clients, alarmPrefix, canary.Name)
+	if err != nil {
+		return err
+	}
+	for _, alarmName := range names {
+		if desired[alarmName] {
+			continue
+		}
+		canary.Logf("Removing alarm %s..", alarmName)
+		err = alarm.New(clients, &alarmName, alarm.Config{}).Remove(ctx)
+		if err != nil {
+			return err
 		}
 	}
 
-	// Check for deploy error
-	if *status.State == "ERROR" {
-		return fmt.Errorf("[%s] Error: %s", canary.Name, *status.StateReason)
+	return nil
+}
+
+func deployGroups(ctx context.Context, clients *aws.Clients, region *string, accountID *string, canary *canary.Canary) error {
+	canaryArn := canary.GetArn(region, accountID)
+
+	// Get current groups
+	current, err := group.ListAssociated(ctx, clients, canaryArn)
+	if err != nil {
+		return err
+	}
+	associated := map[string]bool{}
+	for _, groupName := range current {
+		associated[groupName] = true
+	}
+
+	// Add canary to groups
+	desired := map[string]bool{}
+	for _, groupName := range canary.Groups {
+		desired[groupName] = true
+		if associated[groupName] {
+			continue
+		}
+
+		canary.Logf("Adding to group %s..", groupName)
+		canaryGroup := group.New(clients, &groupName)
+		err = canaryGroup.Deploy(ctx)
+		if err != nil {
+			return err
+		}
+		err = canaryGroup.Associate(ctx, canaryArn)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remove canary from groups no longer in configuration
+	for _, groupName := range current {
+		if desired[groupName] {
+			continue
+		}
+
+		canary.Logf("Removing from group %s..", groupName)
+		canaryGroup := group.New(clients, &groupName)
+		err = canaryGroup.Disassociate(ctx, canaryArn)
+		if err != nil {
+			return err
+		}
+		removed, err := canaryGroup.RemoveIfEmpty(ctx)
+		if err != nil {
+			return err
+		}
+		if removed {
+			canary.Logf("Group %s removed", groupName)
+		}
 	}
 
-	fmt.Println(fmt.Sprintf("[%s] Deploy completed!", canary.Name))
 	return nil
 }
 
+func appendAction(actions []string, action string) []string {
+	for _, current := range actions {
+		if current == action {
+			return actions
+		}
+	}
+
+	// Copy actions to not modify canary configuration
+	return append(append([]string{}, actions...), action)
+}
+
 func cleanTemporaryResources(canary *canary.Canary) {
 	// Clean temporary resources
-	fmt.Println(fmt.Sprintf("[%s] Cleaning temporary resources..", canary.Name))
+	canary.Logf("Cleaning temporary resources..")
 	canary.Code.DeleteArchive()
 }
@@ -0,0 +1,225 @@
+package deploy_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestMain(m *testing.M) {
+	canary.DefaultPollInterval = 10 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+func TestDeployCreatesCanaryAndRole(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	dir, err := fake.WriteCanary(t.TempDir(), "test-deploy", "memory: 1500\nproject: shop\nalarms:\n  - metric: SuccessPercent\n    comparison: \"<\"\n    threshold: 90\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", dir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+
+	// Check canary
+	deployed, ok := backend.Canaries["test-deploy"]
+	if !ok {
+		t.Fatal("canary not deployed")
+	}
+	if state := aws.StringValue(deployed.Canary.Status.State); state != synthetics.CanaryStateReady {
+		t.Errorf("expected canary state %s, found %s", synthetics.CanaryStateReady, state)
+	}
+	if memory := aws.Int64Value(deployed.Canary.RunConfig.MemoryInMB); memory != 1500 {
+		t.Errorf("expected memory 1500, found %d", memory)
+	}
+	if managed := aws.StringValue(deployed.Tags["aws-canary:managed"]); managed != "true" {
+		t.Errorf("expected managed tag, found %q", managed)
+	}
+	if project := aws.StringValue(deployed.Tags["aws-canary:project"]); project != "shop" {
+		t.Errorf("expected project tag shop, found %q", project)
+	}
+
+	// Check role and its inline policy
+	role, ok := backend.Roles["CloudWatchSyntheticsRole-us-east-1-test-deploy"]
+	if !ok {
+		t.Fatal("role not deployed")
+	}
+	if _, ok := role.Policies["CloudWatchSyntheticsPolicy-us-east-1-test-deploy"]; !ok {
+		t.Error("role inline policy not deployed")
+	}
+	if aws.StringValue(deployed.Canary.ExecutionRoleArn) != aws.StringValue(role.Role.Arn) {
+		t.Errorf("canary execution role %s does not match %s", aws.StringValue(deployed.Canary.ExecutionRoleArn), aws.StringValue(role.Role.Arn))
+	}
+
+	// Check alarm
+	if _, ok := backend.Alarms["CloudWatchSyntheticsAlarm-us-east-1-test-deploy-SuccessPercent"]; !ok {
+		t.Error("alarm not deployed")
+	}
+}
+
+func TestDeployUpdatesCanary(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	dir := t.TempDir()
+	canaryDir, err := fake.WriteCanary(dir, "test-update", "memory: 1000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", canaryDir)
+	if err != nil {
+		t.Fatalf("first deploy failed: %s", err)
+	}
+
+	// Change configuration and deploy again
+	_, err = fake.WriteCanary(dir, "test-update", "memory: 2000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", canaryDir)
+	if err != nil {
+		t.Fatalf("second deploy failed: %s", err)
+	}
+
+	deployed := backend.Canaries["test-update"]
+	if memory := aws.Int64Value(deployed.Canary.RunConfig.MemoryInMB); memory != 2000 {
+		t.Errorf("expected updated memory 2000, found %d", memory)
+	}
+	if len(backend.Canaries) != 1 {
+		t.Errorf("expected 1 canary, found %d", len(backend.Canaries))
+	}
+}
+
+func TestDeployUpdatesOwnershipTags(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	artifactBucket := "cw-syn-results-" + backend.AccountID + "-" + backend.Region
+	backend.BucketTags[artifactBucket] = map[string]string{"team": "qa"}
+	dir := t.TempDir()
+	config := "notifications:\n  subscriptions:\n    - protocol: email\n      endpoint: alerts@example.com\nalarms:\n  - metric: Failed\n    comparison: \">\"\n    threshold: 0\n"
+
+	// Deploy without project, then move canary to a project
+	for _, project := range []string{"", "shop"} {
+		canaryDir, err := fake.WriteCanary(dir, "test-tags", "project: \""+project+"\"\n"+config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", "--project", project, canaryDir)
+		if err != nil {
+			t.Fatalf("deploy failed: %s", err)
+		}
+	}
+
+	topic, ok := backend.Topics["arn:aws:sns:us-east-1:"+backend.AccountID+":CloudWatchSyntheticsTopic-us-east-1-test-tags"]
+	if !ok {
+		t.Fatal("topic not deployed")
+	}
+	if project := topic.Tags["aws-canary:project"]; project != "shop" {
+		t.Errorf("expected topic project tag shop, found %q", project)
+	}
+	if project := backend.AlarmTags["CloudWatchSyntheticsAlarm-us-east-1-test-tags-Failed"]["aws-canary:project"]; project != "shop" {
+		t.Errorf("expected alarm project tag shop, found %q", project)
+	}
+	if managed := backend.BucketTags[artifactBucket]["aws-canary:managed"]; managed != "true" {
+		t.Errorf("expected bucket managed tag, found %q", managed)
+	}
+	if project := backend.BucketTags[artifactBucket]["aws-canary:project"]; project != "shop" {
+		t.Errorf("expected bucket project tag shop, found %q", project)
+	}
+	if team := backend.BucketTags[artifactBucket]["team"]; team != "qa" {
+		t.Errorf("expected bucket tag team to be preserved, found %q", team)
+	}
+}
+
+func TestDeploySafeUpdatesOnlyWhenDryRunPasses(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	dir := t.TempDir()
+	canaryDir, err := fake.WriteCanary(dir, "test-safe", "memory: 1000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", "--safe", canaryDir)
+	if err != nil {
+		t.Fatalf("first deploy failed: %s", err)
+	}
+	if dryRuns := len(backend.Canaries["test-safe"].DryRuns); dryRuns != 0 {
+		t.Errorf("expected no dry runs on create, found %d", dryRuns)
+	}
+
+	// Failed dry run leaves the canary and its role policy untouched
+	role := backend.Roles["CloudWatchSyntheticsRole-us-east-1-test-safe"]
+	policy := role.Policies["CloudWatchSyntheticsPolicy-us-east-1-test-safe"]
+	_, err = fake.WriteCanary(dir, "test-safe", "memory: 2000\npolicies:\n  - Effect: Allow\n    Action:\n      - dynamodb:ListTables\n    Resource:\n      - \"*\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.FailingRuns["test-safe"] = "element not found"
+	output, err := captureOutput(func() error {
+		return fake.Run(backend, deploy.NewCommand, "--yes", "--all", "--safe", canaryDir)
+	})
+	if err == nil {
+		t.Fatal("expected deploy to fail when dry run fails")
+	}
+	if !strings.Contains(output, "Dry run failed, canary not updated: element not found") || !strings.Contains(output, "INFO: Canary test-safe dry run") {
+		t.Errorf("expected dry run failure with logs, found output:\n%s", output)
+	}
+	deployed := backend.Canaries["test-safe"]
+	if memory := aws.Int64Value(deployed.Canary.RunConfig.MemoryInMB); memory != 1000 {
+		t.Errorf("expected memory to stay 1000, found %d", memory)
+	}
+	if current := role.Policies["CloudWatchSyntheticsPolicy-us-east-1-test-safe"]; current != policy {
+		t.Errorf("expected role policy unchanged, found %s", current)
+	}
+
+	// Passed dry run applies the update
+	delete(backend.FailingRuns, "test-safe")
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", "--safe", canaryDir)
+	if err != nil {
+		t.Fatalf("safe deploy failed: %s", err)
+	}
+	if memory := aws.Int64Value(deployed.Canary.RunConfig.MemoryInMB); memory != 2000 {
+		t.Errorf("expected updated memory 2000, found %d", memory)
+	}
+	if dryRuns := len(deployed.DryRuns); dryRuns != 2 {
+		t.Errorf("expected 2 dry runs, found %d", dryRuns)
+	}
+	if current := role.Policies["CloudWatchSyntheticsPolicy-us-east-1-test-safe"]; !strings.Contains(current, "dynamodb:ListTables") {
+		t.Errorf("expected updated role policy, found %s", current)
+	}
+}
+
+func captureOutput(fn func() error) (string, error) {
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	os.Stdout = writer
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	output := make(chan string)
+	go func() {
+		var buffer bytes.Buffer
+		io.Copy(&buffer, reader)
+		output <- buffer.String()
+	}()
+
+	err = fn()
+	writer.Close()
+	return <-output, err
+}
@@ -0,0 +1,195 @@
+package drift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/bucket"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/iam"
+	"github.com/daaru00/aws-canary-cli/internal/pool"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return drift commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "drift",
+		Usage: "Compare Synthetics Canaries configuration with deployed ones",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "fix",
+				Usage: "Deploy drifted canaries",
+			},
+			&cli.StringFlag{
+				Name:    "artifact-bucket",
+				Usage:   "Then artifact bucket name",
+				EnvVars: []string{"CANARY_ARTIFACT_BUCKET", "CANARY_ARTIFACT_BUCKET_NAME"},
+			},
+			&cli.StringFlag{
+				Name:    "sources-bucket",
+				Usage:   "Then source code bucket name",
+				EnvVars: []string{"CANARY_SOURCES_BUCKET", "CANARY_SOURCES_BUCKET_NAME"},
+			},
+			&cli.BoolFlag{
+				Name:    "upload",
+				Aliases: []string{"u"},
+				Usage:   "Upload code to source bucket when fixing drifted canaries",
+			},
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of canaries processed in parallel (0 means no limit)",
+				Value:   5,
+				EnvVars: []string{"CANARY_CONCURRENCY"},
+			},
+			&cli.BoolFlag{
+				Name:    "all",
+				Aliases: []string{"a"},
+				Usage:   "Select all canaries",
+			},
+		}...),
+		Action:    Action,
+		ArgsUsage: "[path...]",
+	}
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get caller infos
+	accountID := aws.GetCallerAccountID(clients)
+	region := aws.GetCallerRegion(clients)
+	if accountID == nil {
+		return errors.New("No valid AWS credentials found")
+	}
+
+	// Get artifact bucket
+	artifactBucketName := c.String("artifact-bucket")
+	if len(artifactBucketName) == 0 {
+		artifactBucketName = fmt.Sprintf("cw-syn-results-%s-%s", *accountID, *region)
+	}
+	artifactBucket := bucket.New(clients, &artifactBucketName)
+
+	// Get canaries
+	canaries, err := config.LoadCanaries(c, clients)
+	if err != nil {
+		return err
+	}
+
+	// Ask canaries selection
+	canaries, err = config.AskMultipleCanariesSelection(c, *canaries)
+	if err != nil {
+		return err
+	}
+
+	// Check drift
+	drifted := []*canary.Canary{}
+	for _, canary := range *canaries {
+		differences, err := getDifferences(c.Context, clients, region, accountID, canary, artifactBucket)
+		if err != nil {
+			return err
+		}
+		if len(differences) == 0 {
+			canary.Logf("No drift detected")
+			continue
+		}
+
+		for _, difference := range differences {
+			canary.Logf("Drift on %s: expected %q, found %q", difference.Field, difference.Expected, difference.Actual)
+		}
+		drifted = append(drifted, canary)
+	}
+
+	// Check drifted canaries
+	if len(drifted) == 0 {
+		return nil
+	}
+	if c.Bool("fix") == false {
+		return fmt.Errorf("%d of %d canaries drifted", len(drifted), len(*canaries))
+	}
+
+	// Get source bucket
+	var sourceBucket *bucket.Bucket
+	if c.Bool("upload") {
+		sourceBucketName := c.String("sources-bucket")
+		if len(sourceBucketName) == 0 {
+			sourceBucketName = fmt.Sprintf("cw-syn-sources-%s-%s", *accountID, *region)
+		}
+		sourceBucket = bucket.New(clients, &sourceBucketName)
+	}
+
+	// Deploy drifted canaries
+	fmt.Println("")
+	retryPolicy := aws.NewRetryPolicy(c)
+	summary := pool.New(c.Int("concurrency"), 0).Run(c.Context, drifted, func(ctx context.Context, canary *canary.Canary) error {
+		return deploy.SingleCanary(ctx, clients, retryPolicy, region, accountID, canary, artifactBucket, sourceBucket, nil, false)
+	})
+	summary.Print()
+
+	// Check errors
+	inError := summary.Count(pool.StatusFailed, pool.StatusInterrupted)
+	if inError > 0 {
+		return fmt.Errorf("%d of %d drifted canaries fail deploy", inError, len(drifted))
+	}
+
+	return nil
+}
+
+func getDifferences(ctx context.Context, clients *aws.Clients, region *string, accountID *string, selectedCanary *canary.Canary, artifactBucket *bucket.Bucket) ([]*canary.Difference, error) {
+	// Check if deployed
+	if selectedCanary.IsDeployed(ctx) == false {
+		return []*canary.Difference{{
+			Field:    "canary",
+			Expected: "deployed",
+			Actual:   "not deployed",
+		}}, nil
+	}
+
+	// Check role, policy is managed only for roles created by the CLI
+	if len(selectedCanary.RoleName) > 0 {
+		role := iam.NewRole(clients, &selectedCanary.RoleName)
+		return selectedCanary.GetDrift(ctx, region, accountID, *role.Arn)
+	}
+
+	roleName := fmt.Sprintf("CloudWatchSyntheticsRole-%s-%s", *region, selectedCanary.Name)
+	role := iam.NewRole(clients, &roleName)
+	differences, err := selectedCanary.GetDrift(ctx, region, accountID, *role.Arn)
+	if err != nil {
+		return differences, err
+	}
+
+	// Compare role policy
+	policyName := fmt.Sprintf("CloudWatchSyntheticsPolicy-%s-%s", *region, selectedCanary.Name)
+	artifactKmsKey := selectedCanary.ArtifactConfig.GetKmsKey()
+	policy, err := deploy.BuildIamPolicy(clients, &policyName, artifactBucket, &artifactKmsKey, &selectedCanary.PolicyStatements, region, accountID)
+	if err != nil {
+		return differences, err
+	}
+	document, err := role.GetInlinePolicyDocument(ctx, &policyName)
+	if err != nil {
+		return append(differences, &canary.Difference{
+			Field:    "role.policy",
+			Expected: policyName,
+			Actual:   "not found",
+		}), nil
+	}
+	equivalent, err := policy.IsEquivalent(document)
+	if err != nil {
+		return differences, err
+	}
+	if equivalent == false {
+		differences = append(differences, &canary.Difference{
+			Field:    "role.policy",
+			Expected: "policy built from configuration",
+			Actual:   "modified policy document",
+		})
+	}
+
+	return differences, nil
+}
@@ -0,0 +1,45 @@
+package drift_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/cmd/drift"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestMain(m *testing.M) {
+	canary.DefaultPollInterval = 10 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+func TestDriftUsesDeployTags(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	canaryDir, err := fake.WriteCanary(t.TempDir(), "test-drift", "project: shop\ntags:\n  Team: qa\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", canaryDir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+
+	// Freshly deployed canary has no drift
+	err = fake.Run(backend, drift.NewCommand, "--all", canaryDir)
+	if err != nil {
+		t.Fatalf("expected no drift, got: %s", err)
+	}
+
+	// Changed ownership tag is detected
+	backend.Canaries["test-drift"].Tags["aws-canary:project"] = aws.String("other")
+	err = fake.Run(backend, drift.NewCommand, "--all", canaryDir)
+	if err == nil {
+		t.Fatal("expected drift on project tag")
+	}
+}
@@ -0,0 +1,165 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/group"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return groups commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "groups",
+		Usage: "Return Synthetics Groups and their canaries status",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "members",
+				Aliases: []string{"m"},
+				Usage:   "Also return group canaries",
+			},
+		}...),
+		Action:    Action,
+		ArgsUsage: "[group...]",
+	}
+}
+
+// Member structure, a canary associated to a group
+type Member struct {
+	Canary  string
+	Region  string
+	State   string
+	LastRun string
+	Reason  string
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	var err error
+
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get groups
+	groups := []*group.Group{}
+	if c.Args().Len() > 0 {
+		for _, groupName := range c.Args().Slice() {
+			groupName := groupName
+			selectedGroup := group.New(clients, &groupName)
+			if selectedGroup.IsDeployed(c.Context) == false {
+				return fmt.Errorf("Group %s not found", groupName)
+			}
+			groups = append(groups, selectedGroup)
+		}
+	} else {
+		groups, err = group.List(c.Context, clients)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check groups
+	if len(groups) == 0 {
+		fmt.Println("No groups found")
+		return nil
+	}
+
+	// Print aggregated status
+	if c.Bool("members") == false {
+		fmt.Println(fmt.Sprintf("%-25s\t%-8s\t%-8s\t%-8s\t%s", "Group", "Canaries", "Passed", "Failed", "Status"))
+	}
+	for i, selectedGroup := range groups {
+		members, err := getMembers(c.Context, clients, selectedGroup)
+		if err != nil {
+			return err
+		}
+
+		passed, failed := 0, 0
+		for _, member := range members {
+			if member.LastRun == "PASSED" {
+				passed++
+			} else if member.LastRun == "FAILED" {
+				failed++
+			}
+		}
+		status := "-"
+		if failed > 0 {
+			status = "FAILED"
+		} else if passed > 0 {
+			status = "PASSED"
+		}
+
+		if c.Bool("members") == false {
+			fmt.Println(fmt.Sprintf("%-25s\t%-8d\t%-8d\t%-8d\t%s", *selectedGroup.Name, len(members), passed, failed, status))
+			continue
+		}
+
+		// Print group members
+		if i > 0 {
+			fmt.Println("")
+		}
+		fmt.Println(fmt.Sprintf("[%s] %d canaries, %d passed, %d failed", *selectedGroup.Name, len(members), passed, failed))
+		if len(members) == 0 {
+			continue
+		}
+		fmt.Println(fmt.Sprintf("%-25s\t%-15s\t%-10s\t%-10s\t%s", "Canary", "Region", "State", "Last run", "Reason"))
+		for _, member := range members {
+			fmt.Println(fmt.Sprintf("%-25s\t%-15s\t%-10s\t%-10s\t%s", member.Canary, member.Region, member.State, member.LastRun, member.Reason))
+		}
+	}
+
+	return nil
+}
+
+func getMembers(ctx context.Context, clients *aws.Clients, selectedGroup *group.Group) ([]*Member, error) {
+	members := []*Member{}
+
+	resources, err := selectedGroup.GetResources(ctx)
+	if err != nil {
+		return members, err
+	}
+
+	for _, resourceArn := range resources {
+		// Parse canary ARN, for example arn:aws:synthetics:us-east-1:123456789012:canary:test
+		parts := strings.Split(resourceArn, ":")
+		if len(parts) < 7 {
+			continue
+		}
+		member := &Member{
+			Canary:  parts[6],
+			Region:  parts[3],
+			State:   "-",
+			LastRun: "-",
+		}
+		members = append(members, member)
+
+		// Canaries from other regions are not reachable with current clients
+		if member.Region != *aws.GetCallerRegion(clients) {
+			continue
+		}
+
+		memberCanary := canary.New(clients, member.Canary)
+		status, err := memberCanary.GetStatus(ctx)
+		if err != nil {
+			return members, err
+		}
+		member.State = *status.State
+
+		run, err := memberCanary.GetLastRun(ctx)
+		if err != nil {
+			return members, err
+		}
+		if run != nil {
+			member.LastRun = *run.Status.State
+			if run.Status.StateReason != nil {
+				member.Reason = *run.Status.StateReason
+			}
+		}
+	}
+
+	return members, nil
+}
@@ -0,0 +1,72 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/bucket"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return history commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "history",
+		Usage: "Return Synthetics Canary deploy history",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.StringFlag{
+				Name:    "sources-bucket",
+				Usage:   "Then source code bucket name",
+				EnvVars: []string{"CANARY_SOURCES_BUCKET", "CANARY_SOURCES_BUCKET_NAME"},
+			},
+		}...),
+		Action:    Action,
+		ArgsUsage: "<canary>",
+	}
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Check canary name argument
+	if c.Args().Len() != 1 {
+		return errors.New("Canary name argument is required")
+	}
+	canaryName := c.Args().First()
+
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get caller infos
+	accountID := aws.GetCallerAccountID(clients)
+	region := aws.GetCallerRegion(clients)
+	if accountID == nil {
+		return errors.New("No valid AWS credentials found")
+	}
+
+	// Get source bucket
+	sourceBucketName := c.String("sources-bucket")
+	if len(sourceBucketName) == 0 {
+		sourceBucketName = fmt.Sprintf("cw-syn-sources-%s-%s", *accountID, *region)
+	}
+	sourceBucket := bucket.New(clients, &sourceBucketName)
+
+	// Get history
+	history, err := canary.New(clients, canaryName).GetHistory(c.Context, sourceBucket, region)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("No deploy history found for canary %s, deploy it with --upload flag", canaryName)
+	}
+
+	// Print history, newest first
+	fmt.Println(fmt.Sprintf("%-20s\t%-19s\t%-8s\t%-8s\t%s", "Version", "Date", "Commit", "Action", "User"))
+	for i := len(history) - 1; i >= 0; i-- {
+		version := history[i]
+		fmt.Println(fmt.Sprintf("%-20s\t%-19s\t%-8s\t%-8s\t%s", version.ID, version.Date.Local().Format("2006-01-02 15:04:05"), version.GetShortCommit(), version.Action, version.User))
+	}
+
+	return nil
+}
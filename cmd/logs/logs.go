@@ -1,27 +1,51 @@
 package logs
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/synthetics"
 	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
 	"github.com/daaru00/aws-canary-cli/internal/config"
 	"github.com/urfave/cli/v2"
 )
 
+// ANSI colors used for canaries prefixes
+var colors = []string{"36", "33", "32", "35", "34", "31"}
+
 // NewCommand - Return start commands
 func NewCommand(globalFlags []cli.Flag) *cli.Command {
 	return &cli.Command{
 		Name:  "logs",
 		Usage: "Return Synthetics Canary Run logs",
-		Flags: append(globalFlags, []cli.Flag{
+		Flags: append(globalFlags, append([]cli.Flag{
 			&cli.BoolFlag{
 				Name:    "last",
 				Aliases: []string{"l"},
 				Usage:   "Automatically select last canary run",
 			},
-		}...),
+			&cli.BoolFlag{
+				Name:    "follow",
+				Aliases: []string{"f"},
+				Usage:   "Stream canaries logs from CloudWatch Logs",
+			},
+			&cli.StringFlag{
+				Name:  "filter",
+				Usage: "CloudWatch Logs filter pattern, used with follow",
+			},
+			&cli.BoolFlag{
+				Name:    "all",
+				Aliases: []string{"a"},
+				Usage:   "Select all canaries, used with follow",
+			},
+		}, config.RunsFilterFlags()...)...),
 		Action:    Action,
 		ArgsUsage: "[path...]",
 	}
@@ -29,15 +53,20 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 
 // Action contain the command flow
 func Action(c *cli.Context) error {
-	// Create AWS session
-	ses := aws.NewAwsSession(c)
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
 
 	// Get canaries
-	canaries, err := config.LoadCanaries(c, ses)
+	canaries, err := config.LoadCanaries(c, clients)
 	if err != nil {
 		return err
 	}
 
+	// Stream logs
+	if c.Bool("follow") {
+		return follow(c, *canaries)
+	}
+
 	// Ask canaries selection
 	canary, err := config.AskSingleCanarySelection(c, *canaries)
 	if err != nil {
@@ -45,12 +74,16 @@ func Action(c *cli.Context) error {
 	}
 
 	// Check if deployed
-	if canary.IsDeployed() == false {
+	if canary.IsDeployed(c.Context) == false {
 		return fmt.Errorf("Canary %s not yet deployed", canary.Name)
 	}
 
 	// Retrieve runs
-	runs, err := canary.GetRuns()
+	filter, err := config.GetRunsFilter(c)
+	if err != nil {
+		return err
+	}
+	runs, err := canary.ListRuns(c.Context, filter)
 	if err != nil {
 		return err
 	}
@@ -72,7 +105,7 @@ func Action(c *cli.Context) error {
 	}
 
 	// Get run log
-	logs, err := canary.GetRunLogs(run)
+	logs, err := canary.GetRunLogs(c.Context, run)
 	if err != nil {
 		return err
 	}
@@ -82,3 +115,75 @@ func Action(c *cli.Context) error {
 
 	return nil
 }
+
+func follow(c *cli.Context, canaries []*canary.Canary) error {
+	// Ask canaries selection
+	selected, err := config.AskMultipleCanariesSelection(c, canaries)
+	if err != nil {
+		return err
+	}
+
+	// Start from now unless a time is provided
+	since := time.Now()
+	if len(c.String("since")) > 0 {
+		since, err = config.ParseTime(c.String("since"), since)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check if deployed
+	for _, canary := range *selected {
+		if canary.IsDeployed(c.Context) == false {
+			return fmt.Errorf("Canary %s not yet deployed", canary.Name)
+		}
+	}
+
+	// Follow logs in parallel, stop all when one fails
+	ctx, cancel := context.WithCancel(c.Context)
+	defer cancel()
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	errs := make([]error, len(*selected))
+	colored := isTerminal()
+	for i, selectedCanary := range *selected {
+		prefix := fmt.Sprintf("[%s]", selectedCanary.Name)
+		if colored {
+			prefix = fmt.Sprintf("\033[%sm%s\033[0m", colors[i%len(colors)], prefix)
+		}
+
+		wg.Add(1)
+		go func(i int, canary *canary.Canary, prefix string) {
+			defer wg.Done()
+			err := canary.FollowLogs(ctx, since, c.String("filter"), func(event *cloudwatchlogs.FilteredLogEvent) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				for _, line := range strings.Split(strings.TrimRight(*event.Message, "\n"), "\n") {
+					fmt.Println(fmt.Sprintf("%s %s", prefix, line))
+				}
+			})
+			if err != nil && ctx.Err() == nil {
+				errs[i] = err
+				cancel()
+			}
+		}(i, selectedCanary, prefix)
+	}
+	wg.Wait()
+
+	// Check errors, interruption stops following
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("[%s] Error: %s", (*selected)[i].Name, err)
+		}
+	}
+
+	return nil
+}
+
+func isTerminal() bool {
+	if len(os.Getenv("NO_COLOR")) > 0 {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
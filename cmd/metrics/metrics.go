@@ -0,0 +1,257 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// Sparkline bars, from lowest to highest value
+var bars = []rune("▁▂▃▄▅▆▇█")
+
+// Maximum sparkline length, values are grouped when exceeded
+const sparklineWidth = 48
+
+// NewCommand - Return metrics commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "metrics",
+		Usage: "Return Synthetics Canary metrics",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.StringFlag{
+				Name:  "period",
+				Usage: "Metrics period, for example 5m, 1h or 1d",
+				Value: "1h",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Return metrics after a relative duration (for example 24h or 7d) or a date (for example 2021-03-01)",
+				Value: "7d",
+			},
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: "Return metrics before a relative duration (for example 24h or 7d) or a date (for example 2021-03-01)",
+			},
+			&cli.BoolFlag{
+				Name:    "steps",
+				Aliases: []string{"s"},
+				Usage:   "Also return steps metrics",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Output format: summary, table or json",
+				Value:   "summary",
+			},
+			&cli.BoolFlag{
+				Name:    "all",
+				Aliases: []string{"a"},
+				Usage:   "Select all canaries",
+			},
+		}...),
+		Action:    Action,
+		ArgsUsage: "[path...]",
+	}
+}
+
+// CanaryMetrics structure, the JSON representation of canary metrics
+type CanaryMetrics struct {
+	Canary  string           `json:"canary"`
+	Metrics []*canary.Metric `json:"metrics"`
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Parse time range
+	now := time.Now()
+	since, err := config.ParseTime(c.String("since"), now)
+	if err != nil {
+		return err
+	}
+	until := now
+	if len(c.String("until")) > 0 {
+		until, err = config.ParseTime(c.String("until"), now)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse period, CloudWatch requires a multiple of 60 seconds
+	period, err := config.ParseDuration(c.String("period"))
+	if err != nil {
+		return err
+	}
+	if period < time.Minute || period%time.Minute != 0 {
+		return fmt.Errorf("Invalid period %s, it must be a multiple of 1 minute", c.String("period"))
+	}
+
+	// Check format
+	format := c.String("format")
+	if format != "summary" && format != "table" && format != "json" {
+		return fmt.Errorf("Output format %s not supported, valid formats are summary, table and json", format)
+	}
+
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get canaries
+	canaries, err := config.LoadCanaries(c, clients)
+	if err != nil {
+		return err
+	}
+
+	// Ask canaries selection
+	canaries, err = config.AskMultipleCanariesSelection(c, *canaries)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve metrics
+	results := []*CanaryMetrics{}
+	for _, canary := range *canaries {
+		metrics, err := canary.GetMetrics(c.Context, since, until, period, c.Bool("steps"))
+		if err != nil {
+			return fmt.Errorf("[%s] Error: %s", canary.Name, err)
+		}
+		results = append(results, &CanaryMetrics{
+			Canary:  canary.Name,
+			Metrics: metrics,
+		})
+	}
+
+	// Print metrics
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+	for i, result := range results {
+		if i > 0 {
+			fmt.Println("")
+		}
+		fmt.Println(fmt.Sprintf("[%s] %s - %s, period %s", result.Canary, since.Format("2006-01-02 15:04"), until.Format("2006-01-02 15:04"), period))
+		if format == "table" {
+			printTable(result.Metrics)
+		} else {
+			printSummary(result.Metrics)
+		}
+	}
+
+	return nil
+}
+
+func printSummary(metrics []*canary.Metric) {
+	fmt.Println(fmt.Sprintf("%-15s\t%-25s\t%-10s\t%s", "Metric", "Step", "Value", "Trend"))
+	for _, metric := range metrics {
+		value, ok := metric.Aggregate()
+		formatted := "-"
+		if ok {
+			formatted = formatValue(metric.Name, value)
+		}
+		fmt.Println(fmt.Sprintf("%-15s\t%-25s\t%-10s\t%s", metric.Name, metric.Step, formatted, sparkline(metric.Values)))
+	}
+}
+
+func printTable(metrics []*canary.Metric) {
+	// Collect all timestamps
+	timestamps := []time.Time{}
+	values := make([]map[time.Time]float64, len(metrics))
+	found := map[time.Time]bool{}
+	for i, metric := range metrics {
+		values[i] = map[time.Time]float64{}
+		for j, timestamp := range metric.Timestamps {
+			values[i][timestamp] = metric.Values[j]
+			if found[timestamp] == false {
+				found[timestamp] = true
+				timestamps = append(timestamps, timestamp)
+			}
+		}
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i].Before(timestamps[j])
+	})
+
+	// Print header
+	header := []string{fmt.Sprintf("%-16s", "Timestamp")}
+	for _, metric := range metrics {
+		label := metric.Name
+		if len(metric.Step) > 0 {
+			label = metric.Step + "/" + metric.Name
+		}
+		header = append(header, fmt.Sprintf("%-15s", label))
+	}
+	fmt.Println(strings.Join(header, "\t"))
+
+	// Print rows
+	for _, timestamp := range timestamps {
+		row := []string{fmt.Sprintf("%-16s", timestamp.Local().Format("2006-01-02 15:04"))}
+		for i, metric := range metrics {
+			formatted := "-"
+			if value, ok := values[i][timestamp]; ok {
+				formatted = formatValue(metric.Name, value)
+			}
+			row = append(row, fmt.Sprintf("%-15s", formatted))
+		}
+		fmt.Println(strings.Join(row, "\t"))
+	}
+}
+
+func formatValue(name string, value float64) string {
+	switch name {
+	case "SuccessPercent":
+		return fmt.Sprintf("%.2f%%", value)
+	case "Duration":
+		return (time.Duration(value) * time.Millisecond).Round(time.Millisecond).String()
+	}
+	return fmt.Sprintf("%.0f", value)
+}
+
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	// Group values to fit the sparkline width
+	if len(values) > sparklineWidth {
+		grouped := []float64{}
+		size := int(math.Ceil(float64(len(values)) / sparklineWidth))
+		for i := 0; i < len(values); i += size {
+			end := i + size
+			if end > len(values) {
+				end = len(values)
+			}
+			total := 0.0
+			for _, value := range values[i:end] {
+				total += value
+			}
+			grouped = append(grouped, total/float64(end-i))
+		}
+		values = grouped
+	}
+
+	// Scale values between min and max
+	min, max := values[0], values[0]
+	for _, value := range values {
+		min = math.Min(min, value)
+		max = math.Max(max, value)
+	}
+	line := []rune{}
+	for _, value := range values {
+		index := len(bars) - 1
+		if max > min {
+			index = int((value - min) / (max - min) * float64(len(bars)-1))
+		}
+		line = append(line, bars[index])
+	}
+
+	return string(line)
+}
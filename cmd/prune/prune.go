@@ -0,0 +1,263 @@
+package prune
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/daaru00/aws-canary-cli/cmd/remove"
+	"github.com/daaru00/aws-canary-cli/cmd/stop"
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/bucket"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/iam"
+	"github.com/daaru00/aws-canary-cli/internal/pool"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return prune commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "prune",
+		Usage: "Remove Synthetics Canaries and roles created by the CLI without configuration",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only list orphaned resources without removing them",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Answer yes for all confirmations",
+			},
+			&cli.BoolFlag{
+				Name:  "all-projects",
+				Usage: "Search orphaned resources of all projects, by default only projects of loaded canaries are searched",
+			},
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of canaries processed in parallel (0 means no limit)",
+				Value:   5,
+				EnvVars: []string{"CANARY_CONCURRENCY"},
+			},
+		}...),
+		Action:    Action,
+		ArgsUsage: "[path...]",
+	}
+}
+
+// Orphans structure, resources created by the CLI whose configuration no longer exists
+type Orphans struct {
+	Canaries          []*canary.Canary
+	ArtifactLocations map[string]string
+	Roles             []*iam.Role
+}
+
+// Scope structure, projects whose resources are searched
+type Scope struct {
+	All      bool
+	Projects map[string]bool
+}
+
+// Includes check if project is in scope
+func (s *Scope) Includes(project string) bool {
+	return s.All || (len(project) > 0 && s.Projects[project])
+}
+
+// String return a printable description of the scope
+func (s *Scope) String() string {
+	if s.All {
+		return "all projects"
+	}
+
+	projects := []string{}
+	for project := range s.Projects {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+	return fmt.Sprintf("projects %s", strings.Join(projects, ", "))
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get caller infos
+	accountID := aws.GetCallerAccountID(clients)
+	region := aws.GetCallerRegion(clients)
+	if accountID == nil {
+		return errors.New("No valid AWS credentials found")
+	}
+
+	// Get configured canaries
+	canaries, err := config.LoadCanaries(c, clients)
+	if err != nil {
+		return err
+	}
+	configured := map[string]bool{}
+	scope := &Scope{
+		All:      c.Bool("all-projects"),
+		Projects: map[string]bool{},
+	}
+	for _, canary := range *canaries {
+		configured[canary.Name] = true
+		if len(canary.Project) > 0 {
+			scope.Projects[canary.Project] = true
+		}
+	}
+
+	// Check scope, resources of other projects must never be removed by accident
+	if scope.All == false && len(scope.Projects) == 0 {
+		return errors.New("No project found in loaded canaries, declare it in configuration, use --project parameter or --all-projects to search all projects")
+	}
+	fmt.Println(fmt.Sprintf("Searching orphaned resources of %s..", scope))
+
+	// Search orphaned resources
+	orphans, err := searchOrphans(c.Context, clients, region, configured, scope)
+	if err != nil {
+		return err
+	}
+	if len(orphans.Canaries) == 0 && len(orphans.Roles) == 0 {
+		fmt.Println("No orphaned resources found")
+		return nil
+	}
+
+	// Print orphaned resources
+	fmt.Println(fmt.Sprintf("%-10s\t%s", "Type", "Name"))
+	for _, canary := range orphans.Canaries {
+		fmt.Println(fmt.Sprintf("%-10s\t%s", "canary", canary.Name))
+	}
+	for _, role := range orphans.Roles {
+		fmt.Println(fmt.Sprintf("%-10s\t%s", "role", *role.Name))
+	}
+	fmt.Println("")
+
+	if c.Bool("dry-run") {
+		return nil
+	}
+
+	// Ask confirmation
+	if c.Bool("yes") == false {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Are you sure you want to remove %d canaries and %d roles of %s?", len(orphans.Canaries), len(orphans.Roles), scope),
+		}
+		survey.AskOne(prompt, &confirm)
+
+		// Check respose
+		if confirm == false {
+			return errors.New("Not confirmed orphaned resources remove, skip operation")
+		}
+	}
+
+	// Remove orphaned canaries
+	summary := pool.New(c.Int("concurrency"), 0).Run(c.Context, orphans.Canaries, func(ctx context.Context, canary *canary.Canary) error {
+		err := stop.SingleCanary(ctx, canary)
+		if err != nil && pool.IsSkipped(err) == false {
+			return err
+		}
+
+		err = remove.SingleCanary(ctx, clients, canary, region, accountID)
+		if err != nil {
+			return err
+		}
+
+		return removeArtifacts(ctx, clients, canary, orphans.ArtifactLocations[canary.Name])
+	})
+	if len(orphans.Canaries) > 0 {
+		summary.Print()
+	}
+
+	// Remove orphaned roles
+	for _, role := range orphans.Roles {
+		fmt.Println(fmt.Sprintf("Removing role %s..", *role.Name))
+		err = role.Remove(c.Context)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check errors
+	inError := summary.Count(pool.StatusFailed, pool.StatusInterrupted)
+	if inError > 0 {
+		return fmt.Errorf("%d of %d orphaned canaries fail remove", inError, len(orphans.Canaries))
+	}
+
+	return nil
+}
+
+func searchOrphans(ctx context.Context, clients *aws.Clients, region *string, configured map[string]bool, scope *Scope) (*Orphans, error) {
+	orphans := &Orphans{
+		Canaries:          []*canary.Canary{},
+		ArtifactLocations: map[string]string{},
+		Roles:             []*iam.Role{},
+	}
+
+	// Search canaries created by the CLI for projects in scope
+	deployed, err := canary.ListDeployed(ctx, clients)
+	if err != nil {
+		return orphans, err
+	}
+	live := map[string]bool{}
+	for _, deployedCanary := range deployed {
+		live[*deployedCanary.Name] = true
+		tags := map[string]string{}
+		for key, value := range deployedCanary.Tags {
+			tags[key] = *value
+		}
+		if configured[*deployedCanary.Name] || aws.IsManaged(tags) == false || scope.Includes(tags[aws.ProjectTag]) == false {
+			continue
+		}
+		orphans.Canaries = append(orphans.Canaries, canary.New(clients, *deployedCanary.Name))
+		if deployedCanary.ArtifactS3Location != nil {
+			orphans.ArtifactLocations[*deployedCanary.Name] = *deployedCanary.ArtifactS3Location
+		}
+	}
+
+	// Search roles created by the CLI, roles of deployed canaries are removed with them
+	rolePrefix := fmt.Sprintf("CloudWatchSyntheticsRole-%s-", *region)
+	roles, err := iam.ListRoles(ctx, clients, rolePrefix)
+	if err != nil {
+		return orphans, err
+	}
+	for _, role := range roles {
+		canaryName := strings.TrimPrefix(*role.Name, rolePrefix)
+		if configured[canaryName] || live[canaryName] {
+			continue
+		}
+
+		tags, err := role.GetDeployedTags(ctx)
+		if err != nil {
+			return orphans, err
+		}
+		if aws.IsManaged(tags) == false || scope.Includes(tags[aws.ProjectTag]) == false {
+			continue
+		}
+
+		policyName := fmt.Sprintf("CloudWatchSyntheticsPolicy-%s-%s", *region, canaryName)
+		role.SetInlinePolicy(iam.NewPolicy(clients, &policyName))
+		orphans.Roles = append(orphans.Roles, role)
+	}
+
+	return orphans, nil
+}
+
+func removeArtifacts(ctx context.Context, clients *aws.Clients, selectedCanary *canary.Canary, location string) error {
+	if len(location) == 0 {
+		return nil
+	}
+
+	// Parse artifact location, for example s3://bucket/canary/test
+	parts := strings.SplitN(strings.TrimPrefix(location, "s3://"), "/", 2)
+	if len(parts) < 2 {
+		return nil
+	}
+
+	selectedCanary.Logf("Removing artifacts..")
+	return bucket.New(clients, &parts[0]).EmptyPrefix(ctx, parts[1]+"/")
+}
@@ -1,18 +1,22 @@
 package remove
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"sync"
 
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/daaru00/aws-canary-cli/cmd/dashboard"
 	"github.com/daaru00/aws-canary-cli/cmd/stop"
+	"github.com/daaru00/aws-canary-cli/internal/alarm"
 	"github.com/daaru00/aws-canary-cli/internal/aws"
 	"github.com/daaru00/aws-canary-cli/internal/bucket"
 	"github.com/daaru00/aws-canary-cli/internal/canary"
 	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/group"
 	"github.com/daaru00/aws-canary-cli/internal/iam"
+	"github.com/daaru00/aws-canary-cli/internal/pool"
+	"github.com/daaru00/aws-canary-cli/internal/topic"
 	"github.com/urfave/cli/v2"
 )
 
@@ -46,6 +50,17 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 				Aliases: []string{"y"},
 				Usage:   "Answer yes for all confirmations",
 			},
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of canaries processed in parallel (0 means no limit)",
+				Value:   5,
+				EnvVars: []string{"CANARY_CONCURRENCY"},
+			},
+			&cli.DurationFlag{
+				Name:    "timeout",
+				Usage:   "Maximum duration of each canary operation, for example 10m (0 means no limit)",
+				EnvVars: []string{"CANARY_OPERATION_TIMEOUT"},
+			},
 			&cli.BoolFlag{
 				Name:    "all",
 				Aliases: []string{"a"},
@@ -61,12 +76,12 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 func Action(c *cli.Context) error {
 	var err error
 
-	// Create AWS session
-	ses := aws.NewAwsSession(c)
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
 
 	// Get caller infos
-	accountID := aws.GetCallerAccountID(ses)
-	region := aws.GetCallerRegion(ses)
+	accountID := aws.GetCallerAccountID(clients)
+	region := aws.GetCallerRegion(clients)
 	if accountID == nil {
 		return errors.New("No valid AWS credentials found")
 	}
@@ -84,7 +99,7 @@ func Action(c *cli.Context) error {
 			return err
 		}
 
-		err = removeBucket(ses, &artifactBucketName)
+		err = removeBucket(c.Context, clients, &artifactBucketName)
 		if err != nil {
 			return err
 		}
@@ -103,14 +118,14 @@ func Action(c *cli.Context) error {
 			return err
 		}
 
-		err = removeBucket(ses, &sourceBucketName)
+		err = removeBucket(c.Context, clients, &sourceBucketName)
 		if err != nil {
 			return err
 		}
 	}
 
 	// Get canaries
-	canaries, err := config.LoadCanaries(c, ses)
+	canaries, err := config.LoadCanaries(c, clients)
 	if err != nil {
 		return err
 	}
@@ -127,48 +142,33 @@ func Action(c *cli.Context) error {
 		return err
 	}
 
-	// Setup wait group for async jobs
-	var waitGroup sync.WaitGroup
-
-	// Setup deploy chan error
-	errs := make(chan error, len(*canaries))
-
-	// Loop over found canaries
-	for _, cy := range *canaries {
-
-		// Execute parallel deploy
-		waitGroup.Add(1)
-		go func(canary *canary.Canary) {
-			defer waitGroup.Done()
-			var err error
-
-			if canary.IsDeployed() {
-				err = stop.SingleCanary(canary)
-			}
+	// Execute parallel remove
+	summary := pool.New(c.Int("concurrency"), c.Duration("timeout")).Run(c.Context, *canaries, func(ctx context.Context, canary *canary.Canary) error {
+		var err error
 
-			if err == nil {
-				err = removeSingleCanary(ses, canary, region)
+		if canary.IsDeployed(ctx) {
+			err = stop.SingleCanary(ctx, canary)
+			if pool.IsSkipped(err) {
+				err = nil
 			}
+		}
 
-			errs <- err
-		}(cy)
-	}
+		if err == nil {
+			err = SingleCanary(ctx, clients, canary, region, accountID)
+		}
 
-	// Wait until all remove ends
-	waitGroup.Wait()
+		return err
+	})
+	summary.Print()
 
-	// Close errors channel
-	close(errs)
+	// Remove canaries from dashboards
+	err = removeDashboards(c.Context, clients, region, accountID, *canaries)
+	if err != nil {
+		return err
+	}
 
 	// Check errors
-	var inError int
-	for i := 0; i < len(*canaries); i++ {
-		err := <-errs
-		if err != nil {
-			inError++
-			fmt.Println(err)
-		}
-	}
+	inError := summary.Count(pool.StatusFailed, pool.StatusInterrupted)
 	if inError > 0 {
 		return fmt.Errorf("%d of %d canaries fail remove", inError, len(*canaries))
 	}
@@ -176,19 +176,19 @@ func Action(c *cli.Context) error {
 	return nil
 }
 
-func removeBucket(ses *session.Session, bucketName *string) error {
-	bucket := bucket.New(ses, bucketName)
+func removeBucket(ctx context.Context, clients *aws.Clients, bucketName *string) error {
+	bucket := bucket.New(clients, bucketName)
 
 	// Empty bucket
 	fmt.Println(fmt.Sprintf("Empty bucket %s..", *bucketName))
-	err := bucket.Empty()
+	err := bucket.Empty(ctx)
 	if err != nil {
 		return err
 	}
 
 	// Remove artifact bucket
 	fmt.Println(fmt.Sprintf("Removing bucket %s..", *bucketName))
-	err = bucket.Remove()
+	err = bucket.Remove(ctx)
 	if err != nil {
 		return err
 	}
@@ -196,19 +196,19 @@ func removeBucket(ses *session.Session, bucketName *string) error {
 	return nil
 }
 
-func removeIamRole(ses *session.Session, canary *canary.Canary, roleName *string, policyName *string) error {
-	role := iam.NewRole(ses, roleName)
-	policy := iam.NewPolicy(ses, policyName)
+func removeIamRole(ctx context.Context, clients *aws.Clients, canary *canary.Canary, roleName *string, policyName *string) error {
+	role := iam.NewRole(clients, roleName)
+	policy := iam.NewPolicy(clients, policyName)
 	role.SetInlinePolicy(policy)
 
 	// Check if role is deployed
-	if role.IsDeployed() == false {
+	if role.IsDeployed(ctx) == false {
 		return nil
 	}
 
 	// Remove role
-	fmt.Println(fmt.Sprintf("[%s] Removing role..", canary.Name))
-	err := role.Remove()
+	canary.Logf("Removing role..")
+	err := role.Remove(ctx)
 	if err != nil {
 		return err
 	}
@@ -216,27 +216,164 @@ func removeIamRole(ses *session.Session, canary *canary.Canary, roleName *string
 	return nil
 }
 
-func removeSingleCanary(ses *session.Session, canary *canary.Canary, region *string) error {
+func removeAlarms(ctx context.Context, clients *aws.Clients, canary *canary.Canary, region *string) error {
+	alarmPrefix := fmt.Sprintf("CloudWatchSyntheticsAlarm-%s-%s-", *region, canary.Name)
+
+	// Search canary alarms
+	names, err := alarm.ListNames(ctx, clients, alarmPrefix, canary.Name)
+	if err != nil {
+		return err
+	}
+
+	// Remove alarms
+	if len(names) > 0 {
+		canary.Logf("Removing alarms..")
+	}
+	for _, alarmName := range names {
+		err = alarm.New(clients, &alarmName, alarm.Config{}).Remove(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func removeTopics(ctx context.Context, clients *aws.Clients, canary *canary.Canary, region *string, accountID *string) error {
+	// Remove canary topic
+	canaryTopicName := fmt.Sprintf("CloudWatchSyntheticsTopic-%s-%s", *region, canary.Name)
+	canaryTopic := topic.New(clients, &canaryTopicName, region, accountID)
+	if canaryTopic.IsDeployed(ctx) {
+		canary.Logf("Removing topic..")
+		err := canaryTopic.Remove(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check project topic
+	if canary.Notifications == nil || len(canary.Notifications.Project) == 0 {
+		return nil
+	}
+	projectTopicName := fmt.Sprintf("CloudWatchSyntheticsProjectTopic-%s-%s", *region, canary.Notifications.Project)
+	projectTopic := topic.New(clients, &projectTopicName, region, accountID)
+	if projectTopic.IsDeployed(ctx) == false {
+		return nil
+	}
+
+	// Remove project topic only when no other canary alarm use it
+	used, err := alarm.IsActionUsed(ctx, clients, fmt.Sprintf("CloudWatchSyntheticsAlarm-%s-", *region), *projectTopic.Arn)
+	if err != nil || used {
+		return err
+	}
+	canary.Logf("Removing topic %s..", projectTopicName)
+	return projectTopic.Remove(ctx)
+}
+
+func removeGroups(ctx context.Context, clients *aws.Clients, canary *canary.Canary, region *string, accountID *string) error {
+	canaryArn := canary.GetArn(region, accountID)
+
+	// Search canary groups, configured groups are also checked to clean them up
+	groupNames := append([]string{}, canary.Groups...)
+	if canary.IsDeployed(ctx) {
+		associated, err := group.ListAssociated(ctx, clients, canaryArn)
+		if err != nil {
+			return err
+		}
+		for _, groupName := range associated {
+			canary.Logf("Removing from group %s..", groupName)
+			err = group.New(clients, &groupName).Disassociate(ctx, canaryArn)
+			if err != nil {
+				return err
+			}
+			groupNames = append(groupNames, groupName)
+		}
+	}
+
+	// Remove empty groups created by the CLI
+	checked := map[string]bool{}
+	for _, groupName := range groupNames {
+		if checked[groupName] {
+			continue
+		}
+		checked[groupName] = true
+
+		removed, err := group.New(clients, &groupName).RemoveIfEmpty(ctx)
+		if err != nil {
+			return err
+		}
+		if removed {
+			canary.Logf("Group %s removed", groupName)
+		}
+	}
+
+	return nil
+}
+
+// SingleCanary remove a single canary with its groups associations, alarms, topics and role
+func SingleCanary(ctx context.Context, clients *aws.Clients, canary *canary.Canary, region *string, accountID *string) error {
 	var err error
 
-	if canary.IsDeployed() {
+	// Remove canary from groups
+	err = removeGroups(ctx, clients, canary, region, accountID)
+	if err != nil {
+		return err
+	}
+
+	if canary.IsDeployed(ctx) {
 		// Remove canary
-		fmt.Println(fmt.Sprintf("[%s] Removing..", canary.Name))
-		err = canary.Remove()
+		canary.Logf("Removing..")
+		err = canary.Remove(ctx)
 		if err != nil {
 			return err
 		}
 	}
 
+	// Remove alarms
+	err = removeAlarms(ctx, clients, canary, region)
+	if err != nil {
+		return err
+	}
+
+	// Remove notifications topics
+	err = removeTopics(ctx, clients, canary, region, accountID)
+	if err != nil {
+		return err
+	}
+
 	// Remove role
 	roleName := fmt.Sprintf("CloudWatchSyntheticsRole-%s-%s", *region, canary.Name)
 	policyName := fmt.Sprintf("CloudWatchSyntheticsPolicy-%s-%s", *region, canary.Name)
-	err = removeIamRole(ses, canary, &roleName, &policyName)
+	err = removeIamRole(ctx, clients, canary, &roleName, &policyName)
+	if err != nil {
+		return err
+	}
+
+	canary.Logf("Remove completed!")
+	return nil
+}
+
+func removeDashboards(ctx context.Context, clients *aws.Clients, region *string, accountID *string, canaries []*canary.Canary) error {
+	// Remove only deleted canaries
+	removed := []*canary.Canary{}
+	for _, canary := range canaries {
+		if canary.IsDeployed(ctx) == false {
+			removed = append(removed, canary)
+		}
+	}
+
+	configs, canaryNames, err := dashboard.GroupCanaries(removed)
 	if err != nil {
 		return err
 	}
+	for _, config := range configs {
+		fmt.Println(fmt.Sprintf("Updating dashboard %s..", config.Name))
+		err = dashboard.Update(ctx, clients, region, accountID, config, nil, canaryNames[config.Name])
+		if err != nil {
+			return err
+		}
+	}
 
-	fmt.Println(fmt.Sprintf("[%s] Remove completed!", canary.Name))
 	return nil
 }
 
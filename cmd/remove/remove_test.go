@@ -0,0 +1,90 @@
+package remove_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/cmd/remove"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestMain(m *testing.M) {
+	canary.DefaultPollInterval = 10 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+func TestRemoveDeletesCanaryResources(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	config := "alarms:\n  - metric: SuccessPercent\n    comparison: \"<\"\n    threshold: 90\nnotifications:\n  subscriptions:\n    - protocol: email\n      endpoint: test@example.com\ngroups:\n  - checkout\n"
+	dir, err := fake.WriteCanary(t.TempDir(), "test-remove", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", dir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+	if len(backend.Canaries) != 1 || len(backend.Roles) != 1 || len(backend.Alarms) != 1 || len(backend.Topics) != 1 || len(backend.Groups) != 1 {
+		t.Fatalf("expected canary resources to be deployed")
+	}
+
+	err = fake.Run(backend, remove.NewCommand, "--yes", "--all", dir)
+	if err != nil {
+		t.Fatalf("remove failed: %s", err)
+	}
+
+	if len(backend.Canaries) != 0 {
+		t.Errorf("expected canary to be removed, found %d", len(backend.Canaries))
+	}
+	if len(backend.Roles) != 0 {
+		t.Errorf("expected role to be removed, found %d", len(backend.Roles))
+	}
+	if len(backend.Alarms) != 0 {
+		t.Errorf("expected alarms to be removed, found %d", len(backend.Alarms))
+	}
+	if len(backend.Topics) != 0 {
+		t.Errorf("expected topic to be removed, found %d", len(backend.Topics))
+	}
+	if len(backend.Groups) != 0 {
+		t.Errorf("expected empty managed group to be removed, found %d", len(backend.Groups))
+	}
+}
+
+func TestRemoveKeepsOtherCanaries(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	root := t.TempDir()
+	removedDir, err := fake.WriteCanary(root, "test-removed", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keptDir, err := fake.WriteCanary(root, "test-kept", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", removedDir, keptDir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+
+	err = fake.Run(backend, remove.NewCommand, "--yes", "--all", removedDir)
+	if err != nil {
+		t.Fatalf("remove failed: %s", err)
+	}
+
+	if _, ok := backend.Canaries["test-removed"]; ok {
+		t.Error("expected canary test-removed to be removed")
+	}
+	if _, ok := backend.Canaries["test-kept"]; !ok {
+		t.Error("expected canary test-kept to be kept")
+	}
+	if _, ok := backend.Roles["CloudWatchSyntheticsRole-us-east-1-test-kept"]; !ok {
+		t.Error("expected role of canary test-kept to be kept")
+	}
+}
@@ -1,10 +1,16 @@
 package results
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/service/synthetics"
 	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
 	"github.com/daaru00/aws-canary-cli/internal/config"
 	"github.com/urfave/cli/v2"
 )
@@ -14,25 +20,44 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 	return &cli.Command{
 		Name:  "results",
 		Usage: "Return Synthetics Canary Runs",
-		Flags: append(globalFlags, []cli.Flag{
+		Flags: append(globalFlags, append([]cli.Flag{
 			&cli.BoolFlag{
 				Name:    "last",
 				Aliases: []string{"l"},
 				Usage:   "Return details about last canary run",
 			},
-		}...),
+			&cli.BoolFlag{
+				Name:    "steps",
+				Aliases: []string{"s"},
+				Usage:   "Return canary run steps, parsed from run report",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print results as JSON",
+			},
+		}, config.RunsFilterFlags()...)...),
 		Action:    Action,
 		ArgsUsage: "[path...]",
 	}
 }
 
+// RunResult structure, the JSON representation of a canary run
+type RunResult struct {
+	ID          string         `json:"id"`
+	Status      string         `json:"status"`
+	Reason      string         `json:"reason,omitempty"`
+	StartedAt   *time.Time     `json:"startedAt,omitempty"`
+	CompletedAt *time.Time     `json:"completedAt,omitempty"`
+	Steps       []*canary.Step `json:"steps,omitempty"`
+}
+
 // Action contain the command flow
 func Action(c *cli.Context) error {
-	// Create AWS session
-	ses := aws.NewAwsSession(c)
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
 
 	// Get canaries
-	canaries, err := config.LoadCanaries(c, ses)
+	canaries, err := config.LoadCanaries(c, clients)
 	if err != nil {
 		return err
 	}
@@ -44,12 +69,16 @@ func Action(c *cli.Context) error {
 	}
 
 	// Check if deployed
-	if canary.IsDeployed() == false {
+	if canary.IsDeployed(c.Context) == false {
 		return fmt.Errorf("Canary %s not yet deployed", canary.Name)
 	}
 
 	// Retrieve runs
-	runs, err := canary.GetRuns()
+	filter, err := config.GetRunsFilter(c)
+	if err != nil {
+		return err
+	}
+	runs, err := canary.ListRuns(c.Context, filter)
 	if err != nil {
 		return err
 	}
@@ -59,24 +88,115 @@ func Action(c *cli.Context) error {
 		return errors.New("No run found for canary")
 	}
 
-	// Return last detail
-	if c.Bool("last") {
-		fmt.Println(fmt.Sprintf("Id: %s", *runs[0].Id))
-		fmt.Println(fmt.Sprintf("Status: %s", *runs[0].Status.State))
-		reason := *runs[0].Status.StateReason
-		if len(reason) > 0 {
-			fmt.Println(fmt.Sprintf("Status Reason: %s", reason))
+	// Print runs list
+	if c.Bool("last") == false && c.Bool("steps") == false {
+		if c.Bool("json") {
+			results := []*RunResult{}
+			for _, run := range runs {
+				results = append(results, newRunResult(run))
+			}
+			return printJSON(results)
+		}
+
+		fmt.Println(fmt.Sprintf("%-36s\t%-7s\t%-25s\t%-25s", "Id", "Status", "Started At", "Compleated At"))
+		for _, run := range runs {
+			fmt.Println(fmt.Sprintf("%-36s\t%-7s\t%-25s\t%-25s", *run.Id, *run.Status.State, config.FormatRunTime(run.Timeline.Started), config.FormatRunTime(run.Timeline.Completed)))
 		}
-		fmt.Println(fmt.Sprintf("Started At: %s", *runs[0].Timeline.Started))
-		fmt.Println(fmt.Sprintf("Compleated At: %s", *runs[0].Timeline.Completed))
 		return nil
 	}
 
-	// Print results
-	fmt.Println(fmt.Sprintf("%-36s\t%-7s\t%-25s\t%-25s", "Id", "Status", "Started At", "Compleated At"))
-	for _, run := range runs {
-		fmt.Println(fmt.Sprintf("%-36s\t%-7s\t%-25s\t%-25s", *run.Id, *run.Status.State, *run.Timeline.Started, *run.Timeline.Completed))
+	// Select run
+	run := runs[0]
+	if c.Bool("last") == false {
+		run, err = config.AskSingleCanaryRun(runs)
+		if err != nil {
+			return err
+		}
+	}
+	result := newRunResult(run)
+
+	// Retrieve steps from run report
+	if c.Bool("steps") {
+		report, err := canary.GetRunReport(c.Context, run)
+		if err != nil {
+			return err
+		}
+		result.Steps = report.GetSteps()
+	}
+
+	// Print run details
+	if c.Bool("json") {
+		return printJSON(result)
+	}
+	printRunResult(result)
+	if c.Bool("steps") {
+		printSteps(result.Steps)
 	}
 
 	return nil
 }
+
+func newRunResult(run *synthetics.CanaryRun) *RunResult {
+	result := &RunResult{
+		ID:     *run.Id,
+		Status: *run.Status.State,
+	}
+	if run.Status.StateReason != nil {
+		result.Reason = *run.Status.StateReason
+	}
+	if run.Timeline != nil {
+		result.StartedAt = run.Timeline.Started
+		result.CompletedAt = run.Timeline.Completed
+	}
+	return result
+}
+
+func printRunResult(result *RunResult) {
+	fmt.Println(fmt.Sprintf("Id: %s", result.ID))
+	fmt.Println(fmt.Sprintf("Status: %s", result.Status))
+	if len(result.Reason) > 0 {
+		fmt.Println(fmt.Sprintf("Status Reason: %s", result.Reason))
+	}
+	if result.StartedAt != nil {
+		fmt.Println(fmt.Sprintf("Started At: %s", *result.StartedAt))
+	}
+	if result.CompletedAt != nil {
+		fmt.Println(fmt.Sprintf("Compleated At: %s", *result.CompletedAt))
+	}
+}
+
+func printSteps(steps []*canary.Step) {
+	round, _ := time.ParseDuration("1ms")
+
+	fmt.Println("")
+	if len(steps) == 0 {
+		fmt.Println("No steps found in run report")
+		return
+	}
+
+	fmt.Println(fmt.Sprintf("%-3s\t%-30s\t%-9s\t%-10s\t%s", "#", "Step", "Status", "Duration", "Reason"))
+	for i, step := range steps {
+		fmt.Println(fmt.Sprintf("%-3d\t%-30s\t%-9s\t%-10s\t%s", i+1, step.Name, step.Status, step.Duration().Round(round), step.FailureReason))
+
+		// Print step details
+		details := []string{}
+		if step.Request != nil {
+			details = append(details, fmt.Sprintf("Request: %s", step.Request))
+		}
+		if step.Response != nil {
+			details = append(details, fmt.Sprintf("Response: %s", step.Response))
+		}
+		for _, screenshot := range step.Screenshots {
+			details = append(details, fmt.Sprintf("Screenshot: %s %s", screenshot.FileName, screenshot.PageURL))
+		}
+		for _, detail := range details {
+			fmt.Println(fmt.Sprintf("   \t%s", strings.TrimSpace(detail)))
+		}
+	}
+}
+
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
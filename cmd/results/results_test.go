@@ -0,0 +1,50 @@
+package results_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/cmd/results"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestMain(m *testing.M) {
+	canary.DefaultPollInterval = 10 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+func TestResultsListRunningRun(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	dir, err := fake.WriteCanary(t.TempDir(), "test-results", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", dir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+
+	// Runs in progress have no completion time
+	deployed := backend.Canaries["test-results"]
+	deployed.Runs = append(deployed.Runs, &synthetics.CanaryRun{
+		Id:   aws.String("00000001-0000-4000-8000-000000000000"),
+		Name: aws.String("test-results"),
+		Status: &synthetics.CanaryRunStatus{
+			State: aws.String(synthetics.CanaryRunStateRunning),
+		},
+		Timeline: &synthetics.CanaryRunTimeline{
+			Started: aws.Time(time.Now()),
+		},
+	})
+
+	err = fake.Run(backend, results.NewCommand, "--state", synthetics.CanaryRunStateRunning, dir)
+	if err != nil {
+		t.Fatalf("results failed: %s", err)
+	}
+}
@@ -0,0 +1,143 @@
+package rollback
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/bucket"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return rollback commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "rollback",
+		Usage: "Redeploy a previous Synthetics Canary version",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "Version to redeploy, by default the version deployed before the current one",
+			},
+			&cli.StringFlag{
+				Name:    "artifact-bucket",
+				Usage:   "Then artifact bucket name",
+				EnvVars: []string{"CANARY_ARTIFACT_BUCKET", "CANARY_ARTIFACT_BUCKET_NAME"},
+			},
+			&cli.StringFlag{
+				Name:    "sources-bucket",
+				Usage:   "Then source code bucket name",
+				EnvVars: []string{"CANARY_SOURCES_BUCKET", "CANARY_SOURCES_BUCKET_NAME"},
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Answer yes for all confirmations",
+			},
+		}...),
+		Action:    Action,
+		ArgsUsage: "<canary>",
+	}
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Check canary name argument
+	if c.Args().Len() != 1 {
+		return errors.New("Canary name argument is required")
+	}
+	canaryName := c.Args().First()
+
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get caller infos
+	accountID := aws.GetCallerAccountID(clients)
+	region := aws.GetCallerRegion(clients)
+	if accountID == nil {
+		return errors.New("No valid AWS credentials found")
+	}
+
+	// Get buckets
+	artifactBucketName := c.String("artifact-bucket")
+	if len(artifactBucketName) == 0 {
+		artifactBucketName = fmt.Sprintf("cw-syn-results-%s-%s", *accountID, *region)
+	}
+	artifactBucket := bucket.New(clients, &artifactBucketName)
+	sourceBucketName := c.String("sources-bucket")
+	if len(sourceBucketName) == 0 {
+		sourceBucketName = fmt.Sprintf("cw-syn-sources-%s-%s", *accountID, *region)
+	}
+	sourceBucket := bucket.New(clients, &sourceBucketName)
+
+	// Select version
+	selectedCanary := canary.New(clients, canaryName)
+	history, err := selectedCanary.GetHistory(c.Context, sourceBucket, region)
+	if err != nil {
+		return err
+	}
+	version, err := selectVersion(history, c.String("to"))
+	if err != nil {
+		return fmt.Errorf("[%s] Error: %s", canaryName, err)
+	}
+
+	// Ask confirmation
+	if c.Bool("yes") == false {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Are you sure you want to rollback canary %s to version %s deployed at %s?", canaryName, version.ID, version.Date.Local().Format("2006-01-02 15:04:05")),
+		}
+		survey.AskOne(prompt, &confirm)
+
+		// Check respose
+		if confirm == false {
+			return errors.New("Not confirmed canary rollback, skip operation")
+		}
+	}
+
+	// Keep deployed environment variables, they are not stored in configuration snapshot
+	selectedCanary.EnvironmentVariables, err = selectedCanary.GetDeployedEnvironmentVariables(c.Context)
+	if err != nil {
+		return err
+	}
+
+	// Load configuration snapshot
+	err = version.LoadConfig(selectedCanary)
+	if err != nil {
+		return err
+	}
+
+	// Deploy version as a new history entry
+	rollbackVersion := *version
+	rollbackVersion.Action = canary.VersionActionRollback
+	selectedCanary.Logf("Rolling back to version %s..", version.ID)
+	return deploy.SingleCanary(c.Context, clients, aws.NewRetryPolicy(c), region, accountID, selectedCanary, artifactBucket, sourceBucket, &rollbackVersion, false)
+}
+
+func selectVersion(history []*canary.Version, to string) (*canary.Version, error) {
+	if len(history) == 0 {
+		return nil, errors.New("no deploy history found, deploy canary with --upload flag")
+	}
+
+	// Search provided version
+	if len(to) > 0 {
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].ID == to {
+				return history[i], nil
+			}
+		}
+		return nil, fmt.Errorf("version %s not found, use history command to list versions", to)
+	}
+
+	// Search the version deployed before the current one
+	current := history[len(history)-1]
+	for i := len(history) - 2; i >= 0; i-- {
+		if history[i].ID != current.ID {
+			return history[i], nil
+		}
+	}
+	return nil, errors.New("no previous version found")
+}
@@ -0,0 +1,70 @@
+package rollback_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/cmd/rollback"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestMain(m *testing.M) {
+	canary.DefaultPollInterval = 10 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+func TestRollbackRestoresPreviousVersion(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	dir := t.TempDir()
+
+	// Deploy two versions with different code and memory
+	for i, memory := range []string{"1000", "2000"} {
+		canaryDir, err := fake.WriteCanary(dir, "test-rollback", "memory: "+memory+"\nenv:\n  API_KEY: secret\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(path.Join(canaryDir, "index.js"), []byte(strings.Repeat("//\n", i)+"exports.handler = async () => {}\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", "--upload", canaryDir)
+		if err != nil {
+			t.Fatalf("deploy failed: %s", err)
+		}
+	}
+
+	// Check history does not contain environment variables values
+	historyObjects := 0
+	for key, content := range backend.Buckets["cw-syn-sources-"+backend.AccountID+"-"+backend.Region] {
+		if strings.Contains(key, "/history/") {
+			historyObjects++
+			if strings.Contains(string(content), "secret") {
+				t.Errorf("history object %s contains environment variables values", key)
+			}
+		}
+	}
+	if historyObjects != 2 {
+		t.Errorf("expected 2 history objects, found %d", historyObjects)
+	}
+
+	err := fake.Run(backend, rollback.NewCommand, "--yes", "test-rollback")
+	if err != nil {
+		t.Fatalf("rollback failed: %s", err)
+	}
+
+	deployed := backend.Canaries["test-rollback"]
+	if memory := aws.Int64Value(deployed.Canary.RunConfig.MemoryInMB); memory != 1000 {
+		t.Errorf("expected memory 1000, found %d", memory)
+	}
+	if value := aws.StringValue(deployed.EnvironmentVariables["API_KEY"]); value != "secret" {
+		t.Errorf("expected environment variable to be kept, found %q", value)
+	}
+}
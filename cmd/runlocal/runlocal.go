@@ -0,0 +1,97 @@
+package runlocal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/local"
+	"github.com/daaru00/aws-canary-cli/internal/pool"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return run-local commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "run-local",
+		Usage: "Run a Synthetics Canary on the local machine",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.StringFlag{
+				Name:    "out",
+				Aliases: []string{"o"},
+				Usage:   "Local artifacts directory",
+				Value:   path.Join(os.TempDir(), "aws-canary-artifacts"),
+				EnvVars: []string{"CANARY_LOCAL_ARTIFACTS_DIR"},
+			},
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of canaries processed in parallel (0 means no limit)",
+				Value:   1,
+				EnvVars: []string{"CANARY_LOCAL_CONCURRENCY"},
+			},
+			&cli.BoolFlag{
+				Name:    "all",
+				Aliases: []string{"a"},
+				Usage:   "Select all canaries",
+			},
+		}...),
+		Action:    Action,
+		ArgsUsage: "[path...]",
+	}
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get canaries
+	canaries, err := config.LoadCanaries(c, clients)
+	if err != nil {
+		return err
+	}
+
+	// Ask canaries selection
+	canaries, err = config.AskMultipleCanariesSelection(c, *canaries)
+	if err != nil {
+		return err
+	}
+
+	// Execute local runs, canary timeout is applied by the runner
+	summary := pool.New(c.Int("concurrency"), 0).Run(c.Context, *canaries, func(ctx context.Context, canary *canary.Canary) error {
+		return SingleCanary(ctx, canary, c.String("out"))
+	})
+	summary.Print()
+
+	// Check errors
+	inError := summary.Count(pool.StatusFailed, pool.StatusInterrupted)
+	if inError > 0 {
+		return fmt.Errorf("%d of %d canaries failed", inError, len(*canaries))
+	}
+
+	return nil
+}
+
+// SingleCanary run single canary locally
+func SingleCanary(ctx context.Context, canary *canary.Canary, outDir string) error {
+	canary.Logf("Running locally..")
+	result, err := local.Run(ctx, canary, outDir)
+	if len(result.ArtifactsDir) > 0 {
+		defer canary.Logf("Artifacts saved in %s", result.ArtifactsDir)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Check for run error
+	if result.Passed == false {
+		return fmt.Errorf("[%s] Fail: %s", canary.Name, result.Reason)
+	}
+
+	canary.Logf("Passed!")
+	return nil
+}
@@ -0,0 +1,63 @@
+package runtimes
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return runtimes commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "runtimes",
+		Usage: "Return available Synthetics Canary runtimes",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "deprecated",
+				Aliases: []string{"d"},
+				Usage:   "Also return deprecated runtimes",
+			},
+		}...),
+		Action: Action,
+	}
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get runtimes
+	runtimes, err := canary.ListRuntimes(c.Context, clients)
+	if err != nil {
+		return err
+	}
+
+	// Print runtimes
+	fmt.Println(fmt.Sprintf("%-30s\t%-10s\t%-11s\t%-11s\t%s", "Runtime", "Released", "Deprecation", "Status", "Description"))
+	for _, runtime := range runtimes {
+		status := "active"
+		if runtime.IsDeprecated() {
+			if c.Bool("deprecated") == false {
+				continue
+			}
+			status = "deprecated"
+		} else if runtime.IsDeprecating() {
+			status = "deprecating"
+		}
+
+		fmt.Println(fmt.Sprintf("%-30s\t%-10s\t%-11s\t%-11s\t%s", runtime.Name, formatDate(runtime.ReleaseDate), formatDate(runtime.DeprecationDate), status, runtime.Description))
+	}
+
+	return nil
+}
+
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return "-"
+	}
+	return date.Local().Format("2006-01-02")
+}
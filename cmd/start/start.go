@@ -1,14 +1,14 @@
 package start
 
 import (
+	"context"
 	"fmt"
-	"sync"
-	"time"
 
 	"github.com/aws/aws-sdk-go/service/synthetics"
 	"github.com/daaru00/aws-canary-cli/internal/aws"
 	"github.com/daaru00/aws-canary-cli/internal/canary"
 	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/pool"
 	"github.com/urfave/cli/v2"
 )
 
@@ -18,6 +18,22 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 		Name:  "start",
 		Usage: "Start a Synthetics Canary",
 		Flags: append(globalFlags, []cli.Flag{
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of canaries processed in parallel (0 means no limit)",
+				Value:   5,
+				EnvVars: []string{"CANARY_CONCURRENCY"},
+			},
+			&cli.DurationFlag{
+				Name:    "timeout",
+				Usage:   "Maximum duration of each canary operation, for example 10m (0 means no limit)",
+				EnvVars: []string{"CANARY_OPERATION_TIMEOUT"},
+			},
+			&cli.BoolFlag{
+				Name:    "wait",
+				Aliases: []string{"w"},
+				Usage:   "Wait for the triggered run to complete, also for manually executed canaries",
+			},
 			&cli.BoolFlag{
 				Name:    "all",
 				Aliases: []string{"a"},
@@ -31,11 +47,11 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 
 // Action contain the command flow
 func Action(c *cli.Context) error {
-	// Create AWS session
-	ses := aws.NewAwsSession(c)
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
 
 	// Get canaries
-	canaries, err := config.LoadCanaries(c, ses)
+	canaries, err := config.LoadCanaries(c, clients)
 	if err != nil {
 		return err
 	}
@@ -46,40 +62,14 @@ func Action(c *cli.Context) error {
 		return err
 	}
 
-	// Setup wait group for async jobs
-	var waitGroup sync.WaitGroup
-
-	// Setup deploy chan error
-	errs := make(chan error, len(*canaries))
-
-	// Loop over found canaries
-	for _, c := range *canaries {
-
-		// Execute parallel deploy
-		waitGroup.Add(1)
-		go func(c *canary.Canary) {
-			defer waitGroup.Done()
-
-			err := SingleCanary(c)
-			errs <- err
-		}(c)
-	}
-
-	// Wait until all remove ends
-	waitGroup.Wait()
-
-	// Close errors channel
-	close(errs)
+	// Execute parallel start
+	summary := pool.New(c.Int("concurrency"), c.Duration("timeout")).Run(c.Context, *canaries, func(ctx context.Context, canary *canary.Canary) error {
+		return SingleCanary(ctx, canary, c.Bool("wait"))
+	})
+	summary.Print()
 
 	// Check errors
-	var inError int
-	for i := 0; i < len(*canaries); i++ {
-		err := <-errs
-		if err != nil {
-			inError++
-			fmt.Println(err)
-		}
-	}
+	inError := summary.Count(pool.StatusFailed, pool.StatusInterrupted)
 	if inError > 0 {
 		return fmt.Errorf("%d of %d canaries failed", inError, len(*canaries))
 	}
@@ -87,72 +77,55 @@ func Action(c *cli.Context) error {
 	return nil
 }
 
-// SingleCanary start single canary
-func SingleCanary(canary *canary.Canary) error {
+// SingleCanary start single canary, scheduled canaries always wait for the triggered run
+func SingleCanary(ctx context.Context, canary *canary.Canary, wait bool) error {
 	// Check if deployed
-	if canary.IsDeployed() == false {
+	if canary.IsDeployed(ctx) == false {
 		return fmt.Errorf("[%s] Error: not yet deployed", canary.Name)
 	}
 
 	// Get canary status
-	currentStatus, err := canary.GetStatus()
+	currentStatus, err := canary.GetStatus(ctx)
 	if err != nil {
 		return err
 	}
 
 	// Check if already stopped or never started
 	if *currentStatus.State == "RUNNING" {
-		fmt.Println(fmt.Sprintf("[%s] Skipped: not in a startable state %s", canary.Name, *currentStatus.State))
-		return nil
+		canary.Logf("Skipped: not in a startable state %s", *currentStatus.State)
+		return pool.Skip("not in a startable state %s", *currentStatus.State)
 	}
 
 	// Start canary
-	fmt.Println(fmt.Sprintf("[%s] Starting..", canary.Name))
-	err = canary.Start()
+	canary.Logf("Starting..")
+	previousRun, err := canary.GetLastRun(ctx)
 	if err != nil {
 		return err
 	}
-
-	// Stop here if Canary is not manually executed
-	if canary.Schedule.Expression == "rate(0 hour)" || canary.Schedule.Expression == "rate(0 minute)" {
-		fmt.Println(fmt.Sprintf("[%s] Started!", canary.Name))
-		return nil
+	err = canary.Start(ctx)
+	if err != nil {
+		return err
 	}
 
-	// Wait until canary ends
-	fmt.Println(fmt.Sprintf("[%s] Waiting..", canary.Name))
-	var status *synthetics.CanaryStatus
-	for {
-		time.Sleep(1000 * time.Millisecond)
-
-		// Get canary status
-		status, err = canary.GetStatus()
-		if err != nil {
-			return err
-		}
-
-		// Check canary state
-		if status != nil && *status.State != "RUNNING" {
-			time.Sleep(2000 * time.Millisecond)
-			break
-		}
+	// Stop here if Canary is manually executed
+	if wait == false && canary.IsManualSchedule() {
+		canary.Logf("Started!")
+		return nil
 	}
 
-	// Wait until last run become available
-	time.Sleep(5 * 1000 * time.Millisecond)
-
-	// Get last run
-	run, err := canary.GetLastRun()
+	// Wait until the triggered run ends
+	canary.Logf("Waiting..")
+	run, err := canary.WaitRun(ctx, previousRun)
 	if err != nil {
 		return err
 	}
 
 	// Check for run error
-	if *run.Status.State == "FAILED" {
+	if *run.Status.State == synthetics.CanaryRunStateFailed {
 		return fmt.Errorf("[%s] Fail: %s", canary.Name, *run.Status.StateReason)
 	}
 
-	fmt.Println(fmt.Sprintf("[%s] Passed!", canary.Name))
+	canary.Logf("Passed!")
 
 	return nil
 }
@@ -0,0 +1,96 @@
+package start_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/cmd/start"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestMain(m *testing.M) {
+	canary.DefaultPollInterval = 10 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+func deployCanary(t *testing.T, backend *fake.Backend, name string) string {
+	backend.CreateBuckets()
+	dir, err := fake.WriteCanary(t.TempDir(), name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", dir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+	return dir
+}
+
+func TestStartWaitRunPassed(t *testing.T) {
+	backend := fake.NewBackend()
+	dir := deployCanary(t, backend, "test-start")
+
+	err := fake.Run(backend, start.NewCommand, "--wait", "--all", dir)
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+
+	runs := backend.Canaries["test-start"].Runs
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run, found %d", len(runs))
+	}
+	if state := aws.StringValue(runs[0].Status.State); state != synthetics.CanaryRunStatePassed {
+		t.Errorf("expected run state %s, found %s", synthetics.CanaryRunStatePassed, state)
+	}
+}
+
+func TestStartWaitRunFailed(t *testing.T) {
+	backend := fake.NewBackend()
+	dir := deployCanary(t, backend, "test-start-fail")
+	backend.FailingRuns["test-start-fail"] = "element not found"
+
+	err := fake.Run(backend, start.NewCommand, "--wait", "--all", dir)
+	if err == nil {
+		t.Fatal("expected start to fail when the run fails")
+	}
+}
+
+func TestStartWaitRunWithClockSkew(t *testing.T) {
+	backend := fake.NewBackend()
+	dir := deployCanary(t, backend, "test-start-skew")
+	backend.ClockSkew = -time.Minute
+
+	// Previous failed run must not be matched as the started one
+	backend.FailingRuns["test-start-skew"] = "element not found"
+	err := fake.Run(backend, start.NewCommand, "--wait", "--all", dir)
+	if err == nil {
+		t.Fatal("expected first start to fail")
+	}
+	delete(backend.FailingRuns, "test-start-skew")
+
+	err = fake.Run(backend, start.NewCommand, "--wait", "--all", dir)
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if runs := backend.Canaries["test-start-skew"].Runs; len(runs) != 2 {
+		t.Errorf("expected 2 runs, found %d", len(runs))
+	}
+}
+
+func TestStartNotDeployed(t *testing.T) {
+	backend := fake.NewBackend()
+	dir, err := fake.WriteCanary(t.TempDir(), "test-missing", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fake.Run(backend, start.NewCommand, "--all", dir)
+	if err == nil {
+		t.Fatal("expected start to fail for a canary not deployed")
+	}
+}
@@ -1,14 +1,14 @@
 package stop
 
 import (
+	"context"
 	"fmt"
-	"sync"
-	"time"
 
 	"github.com/aws/aws-sdk-go/service/synthetics"
 	"github.com/daaru00/aws-canary-cli/internal/aws"
 	"github.com/daaru00/aws-canary-cli/internal/canary"
 	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/pool"
 	"github.com/urfave/cli/v2"
 )
 
@@ -18,6 +18,17 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 		Name:  "stop",
 		Usage: "Stop a Synthetics Canary",
 		Flags: append(globalFlags, []cli.Flag{
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of canaries processed in parallel (0 means no limit)",
+				Value:   5,
+				EnvVars: []string{"CANARY_CONCURRENCY"},
+			},
+			&cli.DurationFlag{
+				Name:    "timeout",
+				Usage:   "Maximum duration of each canary operation, for example 10m (0 means no limit)",
+				EnvVars: []string{"CANARY_OPERATION_TIMEOUT"},
+			},
 			&cli.BoolFlag{
 				Name:    "all",
 				Aliases: []string{"a"},
@@ -31,11 +42,11 @@ func NewCommand(globalFlags []cli.Flag) *cli.Command {
 
 // Action contain the command flow
 func Action(c *cli.Context) error {
-	// Create AWS session
-	ses := aws.NewAwsSession(c)
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
 
 	// Get canaries
-	canaries, err := config.LoadCanaries(c, ses)
+	canaries, err := config.LoadCanaries(c, clients)
 	if err != nil {
 		return err
 	}
@@ -46,40 +57,12 @@ func Action(c *cli.Context) error {
 		return err
 	}
 
-	// Setup wait group for async jobs
-	var waitGroup sync.WaitGroup
-
-	// Setup deploy chan error
-	errs := make(chan error, len(*canaries))
-
-	// Loop over found canaries
-	for _, c := range *canaries {
-
-		// Execute parallel deploy
-		waitGroup.Add(1)
-		go func(c *canary.Canary) {
-			defer waitGroup.Done()
-
-			err := SingleCanary(c)
-			errs <- err
-		}(c)
-	}
-
-	// Wait until all remove ends
-	waitGroup.Wait()
-
-	// Close errors channel
-	close(errs)
+	// Execute parallel stop
+	summary := pool.New(c.Int("concurrency"), c.Duration("timeout")).Run(c.Context, *canaries, SingleCanary)
+	summary.Print()
 
 	// Check errors
-	var inError int
-	for i := 0; i < len(*canaries); i++ {
-		err := <-errs
-		if err != nil {
-			inError++
-			fmt.Println(err)
-		}
-	}
+	inError := summary.Count(pool.StatusFailed, pool.StatusInterrupted)
 	if inError > 0 {
 		return fmt.Errorf("%d of %d canaries fail stop", inError, len(*canaries))
 	}
@@ -88,48 +71,39 @@ func Action(c *cli.Context) error {
 }
 
 // SingleCanary stop single canary
-func SingleCanary(canary *canary.Canary) error {
+func SingleCanary(ctx context.Context, canary *canary.Canary) error {
 	// Check if deployed
-	if canary.IsDeployed() == false {
+	if canary.IsDeployed(ctx) == false {
 		return fmt.Errorf("[%s] Error: not yet deployed", canary.Name)
 	}
 
 	// Get canary status
-	currentStatus, err := canary.GetStatus()
+	currentStatus, err := canary.GetStatus(ctx)
 	if err != nil {
 		return err
 	}
 
 	// Check if already stopped or never started
 	if *currentStatus.State == "STOPPED" || *currentStatus.State == "READY" || *currentStatus.State == "ERROR" {
-		return nil
+		return pool.Skip("not in a stoppable state %s", *currentStatus.State)
 	}
 
 	// Stop canary
-	fmt.Println(fmt.Sprintf("[%s] Stopping..", canary.Name))
-	err = canary.Stop()
+	canary.Logf("Stopping..")
+	err = canary.Stop(ctx)
 	if err != nil {
 		return err
 	}
 
 	// Wait until canary stop
-	var status *synthetics.CanaryStatus
-	fmt.Println(fmt.Sprintf("[%s] Waiting..", canary.Name))
-	for {
-		time.Sleep(1000 * time.Millisecond)
-
-		// Get canary status
-		status, err = canary.GetStatus()
-		if err != nil {
-			return err
-		}
-
-		// Check canary state
-		if *status.State == "STOPPED" {
-			break
-		}
+	canary.Logf("Waiting..")
+	_, err = canary.WaitStatus(ctx, func(status *synthetics.CanaryStatus) bool {
+		return *status.State == "STOPPED"
+	})
+	if err != nil {
+		return err
 	}
 
-	fmt.Println(fmt.Sprintf("[%s] Stopped!", canary.Name))
+	canary.Logf("Stopped!")
 	return nil
 }
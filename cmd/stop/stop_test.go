@@ -0,0 +1,68 @@
+package stop_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/cmd/start"
+	"github.com/daaru00/aws-canary-cli/cmd/stop"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestMain(m *testing.M) {
+	canary.DefaultPollInterval = 10 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+func TestStopScheduledCanary(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	dir, err := fake.WriteCanary(t.TempDir(), "test-stop", "schedule:\n  expression: \"rate(30 minutes)\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", dir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+	err = fake.Run(backend, start.NewCommand, "--all", dir)
+	if err != nil {
+		t.Fatalf("start failed: %s", err)
+	}
+	if state := aws.StringValue(backend.Canaries["test-stop"].Canary.Status.State); state != synthetics.CanaryStateRunning {
+		t.Fatalf("expected canary state %s, found %s", synthetics.CanaryStateRunning, state)
+	}
+
+	err = fake.Run(backend, stop.NewCommand, "--all", dir)
+	if err != nil {
+		t.Fatalf("stop failed: %s", err)
+	}
+	if state := aws.StringValue(backend.Canaries["test-stop"].Canary.Status.State); state != synthetics.CanaryStateStopped {
+		t.Errorf("expected canary state %s, found %s", synthetics.CanaryStateStopped, state)
+	}
+}
+
+func TestStopNotRunningIsSkipped(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	dir, err := fake.WriteCanary(t.TempDir(), "test-stop-ready", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", dir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+
+	err = fake.Run(backend, stop.NewCommand, "--all", dir)
+	if err != nil {
+		t.Fatalf("expected stop of a not running canary to be skipped, found %s", err)
+	}
+}
@@ -0,0 +1,248 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/cmd/stop"
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/pool"
+	"github.com/daaru00/aws-canary-cli/internal/testreport"
+	"github.com/urfave/cli/v2"
+)
+
+// Report formats, flag name is the format name
+var reportFormats = []string{"junit", "tap", "json"}
+
+// NewCommand - Return test commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "test",
+		Usage: "Run Synthetics Canaries once and report results",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.StringFlag{
+				Name:    "junit",
+				Usage:   "JUnit XML report file path (- for standard output)",
+				EnvVars: []string{"CANARY_TEST_JUNIT_REPORT"},
+			},
+			&cli.StringFlag{
+				Name:    "tap",
+				Usage:   "TAP report file path (- for standard output)",
+				EnvVars: []string{"CANARY_TEST_TAP_REPORT"},
+			},
+			&cli.StringFlag{
+				Name:    "json",
+				Usage:   "JSON report file path (- for standard output)",
+				EnvVars: []string{"CANARY_TEST_JSON_REPORT"},
+			},
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of canaries processed in parallel (0 means no limit)",
+				Value:   5,
+				EnvVars: []string{"CANARY_CONCURRENCY"},
+			},
+			&cli.DurationFlag{
+				Name:    "timeout",
+				Usage:   "Maximum duration of each canary operation, for example 10m (0 means no limit)",
+				EnvVars: []string{"CANARY_OPERATION_TIMEOUT"},
+			},
+			&cli.BoolFlag{
+				Name:    "all",
+				Aliases: []string{"a"},
+				Usage:   "Select all canaries",
+			},
+		}...),
+		Action:    Action,
+		ArgsUsage: "[path...]",
+	}
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get canaries
+	canaries, err := config.LoadCanaries(c, clients)
+	if err != nil {
+		return err
+	}
+
+	// Ask canaries selection
+	canaries, err = config.AskMultipleCanariesSelection(c, *canaries)
+	if err != nil {
+		return err
+	}
+
+	// Check reports written to standard output, logs are moved to standard error to keep it parsable
+	stdoutReports := 0
+	for _, format := range reportFormats {
+		if c.String(format) == "-" {
+			stdoutReports++
+		}
+	}
+	if stdoutReports > 1 {
+		return errors.New("Only one report can be written to standard output")
+	}
+	testPool := pool.New(c.Int("concurrency"), c.Duration("timeout"))
+	if stdoutReports > 0 {
+		testPool.SetOutput(os.Stderr)
+	}
+
+	// Execute parallel test runs
+	results := map[string]*testreport.Result{}
+	var mutex sync.Mutex
+	summary := testPool.Run(c.Context, *canaries, func(ctx context.Context, canary *canary.Canary) error {
+		result, err := SingleCanary(ctx, canary)
+		if result != nil {
+			mutex.Lock()
+			results[canary.Name] = result
+			mutex.Unlock()
+		}
+		return err
+	})
+	summary.Print()
+
+	// Collect results in canaries order
+	testResults := []*testreport.Result{}
+	for _, task := range summary.Tasks {
+		result, ok := results[task.Canary.Name]
+		if !ok {
+			result = &testreport.Result{
+				Canary: task.Canary.Name,
+				Status: testreport.StatusError,
+			}
+			if task.Status == pool.StatusSkipped {
+				result.Status = testreport.StatusSkipped
+			}
+			if task.Err != nil {
+				result.Reason = task.Err.Error()
+			}
+		}
+		result.Duration = task.Duration
+		testResults = append(testResults, result)
+	}
+
+	// Write reports
+	for _, format := range reportFormats {
+		if len(c.String(format)) == 0 {
+			continue
+		}
+		err = writeReport(c.String(format), format, testResults)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check errors, when the report is on standard output the error is printed on standard error
+	inError := summary.Count(pool.StatusFailed, pool.StatusInterrupted)
+	if inError > 0 {
+		message := fmt.Sprintf("%d of %d canaries failed", inError, len(*canaries))
+		if stdoutReports > 0 {
+			return cli.Exit(message, 1)
+		}
+		return errors.New(message)
+	}
+
+	return nil
+}
+
+// SingleCanary trigger a single canary run and wait for its result
+func SingleCanary(ctx context.Context, canary *canary.Canary) (*testreport.Result, error) {
+	// Check if deployed
+	if canary.IsDeployed(ctx) == false {
+		return nil, fmt.Errorf("[%s] Error: not yet deployed", canary.Name)
+	}
+
+	// Get canary status
+	currentStatus, err := canary.GetStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for the current manual run to end before starting a new one
+	if *currentStatus.State == synthetics.CanaryStateRunning && canary.IsManualSchedule() {
+		canary.Logf("Waiting for current run to end..")
+		currentStatus, err = canary.WaitStatus(ctx, func(status *synthetics.CanaryStatus) bool {
+			return *status.State != synthetics.CanaryStateRunning
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Scheduled canaries not running on schedule are stopped again after the run,
+	// starting them enables the schedule
+	restoreStopped := canary.IsManualSchedule() == false && (*currentStatus.State == synthetics.CanaryStateStopped || *currentStatus.State == synthetics.CanaryStateReady)
+
+	// Start canary, scheduled canaries already running are tested with the next run
+	previousRun, err := canary.GetLastRun(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if *currentStatus.State != synthetics.CanaryStateRunning {
+		canary.Logf("Starting..")
+		err = canary.Start(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Wait until run ends
+	canary.Logf("Waiting for run..")
+	run, err := canary.WaitRun(ctx, previousRun)
+	if restoreStopped {
+		stopErr := stop.SingleCanary(ctx, canary)
+		if stopErr != nil && pool.IsSkipped(stopErr) == false && err == nil {
+			err = fmt.Errorf("[%s] Error: cannot stop canary after run: %s", canary.Name, stopErr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &testreport.Result{
+		Canary: canary.Name,
+		RunID:  *run.Id,
+		Status: testreport.StatusPassed,
+	}
+	if *run.Status.State == synthetics.CanaryRunStateFailed {
+		result.Status = testreport.StatusFailed
+		result.Reason = *run.Status.StateReason
+	}
+
+	// Collect steps from run report
+	result.Report, err = canary.GetRunReport(ctx, run)
+	if err != nil {
+		canary.Logf("Warning: %s", err)
+	}
+
+	// Check for run error
+	if result.Status == testreport.StatusFailed {
+		return result, fmt.Errorf("[%s] Fail: %s", canary.Name, result.Reason)
+	}
+
+	canary.Logf("Passed!")
+	return result, nil
+}
+
+func writeReport(filePath string, format string, results []*testreport.Result) error {
+	var output io.Writer = os.Stdout
+	if filePath != "-" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	return testreport.Write(output, format, results)
+}
@@ -0,0 +1,73 @@
+package test_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/cmd/start"
+	"github.com/daaru00/aws-canary-cli/cmd/stop"
+	"github.com/daaru00/aws-canary-cli/cmd/test"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestMain(m *testing.M) {
+	canary.DefaultPollInterval = 10 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+func TestTestKeepsScheduledCanaryStopped(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	dir, err := fake.WriteCanary(t.TempDir(), "test-test", "schedule:\n  expression: \"rate(30 minutes)\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", dir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+
+	tests := []struct {
+		name  string
+		setup func() error
+		state string
+	}{
+		{"ready", func() error { return nil }, synthetics.CanaryStateReady},
+		{"stopped", func() error {
+			err := fake.Run(backend, start.NewCommand, "--all", dir)
+			if err != nil {
+				return err
+			}
+			return fake.Run(backend, stop.NewCommand, "--all", dir)
+		}, synthetics.CanaryStateStopped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			deployed := backend.Canaries["test-test"]
+			if state := aws.StringValue(deployed.Canary.Status.State); state != tt.state {
+				t.Fatalf("expected state %s before test, found %s", tt.state, state)
+			}
+			runs := len(deployed.Runs)
+
+			err = fake.Run(backend, test.NewCommand, "--all", dir)
+			if err != nil {
+				t.Fatalf("test failed: %s", err)
+			}
+			if len(deployed.Runs) != runs+1 {
+				t.Errorf("expected %d runs, found %d", runs+1, len(deployed.Runs))
+			}
+			if state := aws.StringValue(deployed.Canary.Status.State); state != synthetics.CanaryStateStopped {
+				t.Errorf("expected canary stopped after test, found %s", state)
+			}
+		})
+	}
+}
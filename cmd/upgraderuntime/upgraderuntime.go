@@ -0,0 +1,253 @@
+package upgraderuntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/daaru00/aws-canary-cli/internal/pool"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return upgrade-runtime commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "upgrade-runtime",
+		Usage: "Upgrade Synthetics Canary runtime in configuration files",
+		Flags: append(globalFlags, []cli.Flag{
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "Target runtime name, latest select the latest active runtime of the same family",
+				Value: "latest",
+			},
+			&cli.BoolFlag{
+				Name:  "verify",
+				Usage: "Verify new runtime with a dry run of deployed canaries, configuration files and deployed canaries are upgraded only when it passes",
+			},
+			&cli.IntFlag{
+				Name:    "concurrency",
+				Usage:   "Maximum number of canaries processed in parallel (0 means no limit)",
+				Value:   5,
+				EnvVars: []string{"CANARY_CONCURRENCY"},
+			},
+			&cli.DurationFlag{
+				Name:    "timeout",
+				Usage:   "Maximum duration of each canary operation, for example 10m (0 means no limit)",
+				EnvVars: []string{"CANARY_OPERATION_TIMEOUT"},
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Answer yes for all confirmations",
+			},
+			&cli.BoolFlag{
+				Name:    "all",
+				Aliases: []string{"a"},
+				Usage:   "Select all canaries",
+			},
+		}...),
+		Action:    Action,
+		ArgsUsage: "[path...]",
+	}
+}
+
+// Upgrade structure, a canary runtime change
+type Upgrade struct {
+	Canary *canary.Canary
+	From   string
+	To     string
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get canaries
+	canaries, err := config.LoadCanaries(c, clients)
+	if err != nil {
+		return err
+	}
+
+	// Ask canaries selection
+	canaries, err = config.AskMultipleCanariesSelection(c, *canaries)
+	if err != nil {
+		return err
+	}
+
+	// Get runtimes
+	runtimes, err := canary.ListRuntimes(c.Context, clients)
+	if err != nil {
+		return err
+	}
+
+	// Elaborate upgrades
+	upgrades := []*Upgrade{}
+	for _, selectedCanary := range *canaries {
+		target, err := getTargetRuntime(runtimes, selectedCanary, c.String("to"))
+		if err != nil {
+			return err
+		}
+		if target == nil || target.Name == selectedCanary.RuntimeVersion {
+			selectedCanary.Logf("Runtime %s already up to date", selectedCanary.RuntimeVersion)
+			continue
+		}
+		upgrades = append(upgrades, &Upgrade{
+			Canary: selectedCanary,
+			From:   selectedCanary.RuntimeVersion,
+			To:     target.Name,
+		})
+	}
+
+	// Check upgrades
+	if len(upgrades) == 0 {
+		return nil
+	}
+
+	// Print upgrades
+	fmt.Println(fmt.Sprintf("%-25s\t%-30s\t%s", "Canary", "Current runtime", "New runtime"))
+	for _, upgrade := range upgrades {
+		fmt.Println(fmt.Sprintf("%-25s\t%-30s\t%s", upgrade.Canary.Name, upgrade.From, upgrade.To))
+	}
+	fmt.Println("")
+
+	// Ask confirmation
+	if c.Bool("yes") == false {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Are you sure you want to upgrade runtime of %d canaries?", len(upgrades)),
+		}
+		survey.AskOne(prompt, &confirm)
+
+		// Check respose
+		if confirm == false {
+			return errors.New("Not confirmed runtime upgrade, skip operation")
+		}
+	}
+
+	// Verify runtimes before upgrading
+	if c.Bool("verify") {
+		return verify(c, upgrades)
+	}
+
+	// Rewrite configuration files
+	for _, upgrade := range upgrades {
+		err = config.UpdateRuntime(upgrade.Canary.ConfigPath, c.String("config-parser"), upgrade.To)
+		if err != nil {
+			return err
+		}
+		upgrade.Canary.Logf("Runtime upgraded to %s in %s", upgrade.To, upgrade.Canary.ConfigPath)
+	}
+	fmt.Println("Run deploy command to apply new runtimes")
+
+	return nil
+}
+
+func verify(c *cli.Context, upgrades []*Upgrade) error {
+	upgradedCanaries := []*canary.Canary{}
+	upgradesByCanary := map[string]*Upgrade{}
+	for _, upgrade := range upgrades {
+		upgradedCanaries = append(upgradedCanaries, upgrade.Canary)
+		upgradesByCanary[upgrade.Canary.Name] = upgrade
+	}
+
+	// Execute parallel verified upgrades
+	summary := pool.New(c.Int("concurrency"), c.Duration("timeout")).Run(c.Context, upgradedCanaries, func(ctx context.Context, canary *canary.Canary) error {
+		return verifiedUpgrade(ctx, upgradesByCanary[canary.Name], c.String("config-parser"))
+	})
+	summary.Print()
+
+	// Check errors
+	inError := summary.Count(pool.StatusFailed, pool.StatusInterrupted)
+	if inError > 0 {
+		return fmt.Errorf("%d of %d canaries fail runtime upgrade verification", inError, len(upgrades))
+	}
+
+	return nil
+}
+
+// verifiedUpgrade test the new runtime with a dry run of the deployed canary, when it passes the configuration
+// file is rewritten and only the runtime of the deployed canary is updated, so local changes are not deployed
+func verifiedUpgrade(ctx context.Context, upgrade *Upgrade, parser string) error {
+	canary := upgrade.Canary
+
+	// Check if deployed
+	if canary.IsDeployed(ctx) == false {
+		canary.Logf("Skipped: not yet deployed, runtime %s cannot be verified", upgrade.To)
+		return pool.Skip("not yet deployed, runtime %s cannot be verified", upgrade.To)
+	}
+
+	// Test new runtime
+	canary.Logf("Starting dry run with runtime %s..", upgrade.To)
+	run, err := canary.DryRunRuntime(ctx, upgrade.To)
+	if err != nil {
+		return err
+	}
+	if *run.Status.State != synthetics.CanaryRunStatePassed {
+		return fmt.Errorf("[%s] Dry run with runtime %s failed, runtime not upgraded: %s", canary.Name, upgrade.To, canary.GetDryRunFailure(ctx, run))
+	}
+	canary.Logf("Dry run passed")
+
+	// Rewrite configuration file
+	err = config.UpdateRuntime(canary.ConfigPath, parser, upgrade.To)
+	if err != nil {
+		return err
+	}
+	canary.Logf("Runtime upgraded to %s in %s", upgrade.To, canary.ConfigPath)
+
+	// Update deployed canary runtime
+	canary.Logf("Updating runtime..")
+	err = canary.UpdateRuntime(ctx, upgrade.To)
+	if err != nil {
+		return err
+	}
+	status, err := canary.WaitStatus(ctx, func(status *synthetics.CanaryStatus) bool {
+		return *status.State != synthetics.CanaryStateUpdating
+	})
+	if err != nil {
+		return err
+	}
+	if *status.State == synthetics.CanaryStateError {
+		reason := "runtime update failed"
+		if status.StateReason != nil {
+			reason = *status.StateReason
+		}
+		return fmt.Errorf("[%s] Error: %s", canary.Name, reason)
+	}
+
+	canary.Logf("Runtime %s deployed", upgrade.To)
+	return nil
+}
+
+func getTargetRuntime(runtimes []*canary.Runtime, selectedCanary *canary.Canary, to string) (*canary.Runtime, error) {
+	// Search latest runtime of the same family
+	if to == "latest" {
+		target := canary.FindLatestRuntime(runtimes, selectedCanary.GetRuntimeFamily())
+		if target == nil {
+			selectedCanary.Logf("Warning: no active runtime found for %s", selectedCanary.GetRuntimeFamily())
+			return nil, nil
+		}
+
+		// Do not downgrade runtime
+		current := canary.FindRuntime(runtimes, selectedCanary.RuntimeVersion)
+		if current != nil && current.IsDeprecated() == false && canary.CompareRuntimes(current, target) >= 0 {
+			return nil, nil
+		}
+		return target, nil
+	}
+
+	// Check provided runtime
+	target := canary.FindRuntime(runtimes, to)
+	if target == nil {
+		return nil, fmt.Errorf("Runtime %s not found, use runtimes command to list available runtimes", to)
+	}
+	if target.IsDeprecated() {
+		return nil, fmt.Errorf("Runtime %s is deprecated since %s", to, target.DeprecationDate.Local().Format("2006-01-02"))
+	}
+	return target, nil
+}
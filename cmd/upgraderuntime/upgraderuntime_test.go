@@ -0,0 +1,112 @@
+package upgraderuntime_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/cmd/upgraderuntime"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestMain(m *testing.M) {
+	canary.DefaultPollInterval = 10 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+func deployCanary(t *testing.T, backend *fake.Backend, name string) string {
+	backend.CreateBuckets()
+	dir, err := fake.WriteCanary(t.TempDir(), name, "runtime: syn-nodejs-puppeteer-3.9\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fake.Run(backend, deploy.NewCommand, "--yes", "--all", dir)
+	if err != nil {
+		t.Fatalf("deploy failed: %s", err)
+	}
+	return dir
+}
+
+func readConfig(t *testing.T, dir string) string {
+	content, err := ioutil.ReadFile(path.Join(dir, "canary.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestUpgradeRuntimeVerifyPassed(t *testing.T) {
+	backend := fake.NewBackend()
+	dir := deployCanary(t, backend, "test-upgrade")
+
+	// Local changes not yet deployed
+	_, err := fake.WriteCanary(path.Dir(dir), "test-upgrade", "runtime: syn-nodejs-puppeteer-3.9\nmemory: 2000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fake.Run(backend, upgraderuntime.NewCommand, "--yes", "--all", "--verify", dir)
+	if err != nil {
+		t.Fatalf("upgrade failed: %s", err)
+	}
+
+	if config := readConfig(t, dir); !strings.Contains(config, "runtime: syn-nodejs-puppeteer-7.0") {
+		t.Errorf("expected upgraded runtime in configuration, found:\n%s", config)
+	}
+	deployed := backend.Canaries["test-upgrade"]
+	if runtime := aws.StringValue(deployed.Canary.RuntimeVersion); runtime != "syn-nodejs-puppeteer-7.0" {
+		t.Errorf("expected deployed runtime syn-nodejs-puppeteer-7.0, found %s", runtime)
+	}
+	if memory := aws.Int64Value(deployed.Canary.RunConfig.MemoryInMB); memory != 1000 {
+		t.Errorf("expected local memory change not deployed, found %d", memory)
+	}
+	if len(deployed.DryRuns) != 1 {
+		t.Errorf("expected 1 dry run, found %d", len(deployed.DryRuns))
+	}
+}
+
+func TestUpgradeRuntimeVerifyFailedKeepsRuntime(t *testing.T) {
+	backend := fake.NewBackend()
+	dir := deployCanary(t, backend, "test-upgrade-fail")
+	original := readConfig(t, dir)
+	backend.FailingRuns["test-upgrade-fail"] = "element not found"
+
+	err := fake.Run(backend, upgraderuntime.NewCommand, "--yes", "--all", "--verify", dir)
+	if err == nil {
+		t.Fatal("expected upgrade to fail when the dry run fails")
+	}
+
+	if config := readConfig(t, dir); config != original {
+		t.Errorf("expected configuration unchanged, found:\n%s", config)
+	}
+	deployed := backend.Canaries["test-upgrade-fail"]
+	if runtime := aws.StringValue(deployed.Canary.RuntimeVersion); runtime != "syn-nodejs-puppeteer-3.9" {
+		t.Errorf("expected deployed runtime syn-nodejs-puppeteer-3.9, found %s", runtime)
+	}
+	if len(deployed.Runs) != 0 {
+		t.Errorf("expected no canary runs, found %d", len(deployed.Runs))
+	}
+}
+
+func TestUpgradeRuntimeVerifySkipsNotDeployed(t *testing.T) {
+	backend := fake.NewBackend()
+	dir, err := fake.WriteCanary(t.TempDir(), "test-upgrade-new", "runtime: syn-nodejs-puppeteer-3.9\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := readConfig(t, dir)
+
+	err = fake.Run(backend, upgraderuntime.NewCommand, "--yes", "--all", "--verify", dir)
+	if err != nil {
+		t.Fatalf("upgrade failed: %s", err)
+	}
+	if config := readConfig(t, dir); config != original {
+		t.Errorf("expected configuration of not deployed canary unchanged, found:\n%s", config)
+	}
+}
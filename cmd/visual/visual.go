@@ -0,0 +1,187 @@
+package visual
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand - Return visual commands
+func NewCommand(globalFlags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "visual",
+		Usage: "Manage Synthetics Canary visual monitoring baseline",
+		Flags: append(append(globalFlags, []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "set-baseline",
+				Usage: "Set the visual monitoring baseline to the last passed run",
+			},
+			&cli.StringFlag{
+				Name:  "run",
+				Usage: "Canary run id to use as baseline, used with --set-baseline",
+			},
+			&cli.BoolFlag{
+				Name:    "failures",
+				Aliases: []string{"f"},
+				Usage:   "Return screenshots that failed visual comparison in recent runs",
+			},
+		}...), config.RunsFilterFlags()...),
+		Action:    Action,
+		ArgsUsage: "[path]",
+	}
+}
+
+// Action contain the command flow
+func Action(c *cli.Context) error {
+	// Create AWS clients
+	clients := aws.NewAwsClients(c)
+
+	// Get canaries
+	canaries, err := config.LoadCanaries(c, clients)
+	if err != nil {
+		return err
+	}
+
+	// Ask canaries selection
+	canary, err := config.AskSingleCanarySelection(c, *canaries)
+	if err != nil {
+		return err
+	}
+
+	// Check if deployed
+	if canary.IsDeployed(c.Context) == false {
+		return fmt.Errorf("Canary %s not yet deployed", canary.Name)
+	}
+
+	if c.Bool("set-baseline") {
+		return setBaseline(c, canary)
+	}
+	if c.Bool("failures") {
+		return printFailures(c, canary)
+	}
+	return printBaseline(c, canary)
+}
+
+func setBaseline(c *cli.Context, selectedCanary *canary.Canary) error {
+	// Select run
+	runID := c.String("run")
+	if len(runID) == 0 {
+		run, err := selectedCanary.GetLastPassedRun(c.Context)
+		if err != nil {
+			return err
+		}
+		runID = *run.Id
+	} else if runID != canary.VisualBaselineLastRun {
+		_, err := selectedCanary.GetRun(c.Context, runID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update baseline
+	selectedCanary.Logf("Setting baseline to run %s..", runID)
+	err := selectedCanary.SetVisualBaseline(c.Context, runID)
+	if err != nil {
+		return err
+	}
+
+	// Wait until canary is updated
+	status, err := selectedCanary.WaitStatus(c.Context, func(status *synthetics.CanaryStatus) bool {
+		return *status.State != "UPDATING"
+	})
+	if err != nil {
+		return err
+	}
+	if *status.State == "ERROR" {
+		return fmt.Errorf("[%s] Error: %s", selectedCanary.Name, *status.StateReason)
+	}
+
+	selectedCanary.Logf("Baseline updated!")
+	return nil
+}
+
+func printBaseline(c *cli.Context, selectedCanary *canary.Canary) error {
+	reference, err := selectedCanary.GetVisualReference(c.Context)
+	if err != nil {
+		return err
+	}
+	if reference == nil || reference.BaseCanaryRunId == nil {
+		return fmt.Errorf("No visual monitoring baseline set for canary %s, use --set-baseline flag", selectedCanary.Name)
+	}
+
+	// Get baseline run
+	run, err := selectedCanary.GetRun(c.Context, *reference.BaseCanaryRunId)
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmt.Sprintf("[%s] Baseline run %s started at %s", selectedCanary.Name, *run.Id, run.Timeline.Started.Local().Format("2006-01-02 15:04:05")))
+
+	// Collect ignored areas
+	ignored := map[string][]string{}
+	for _, screenshot := range reference.BaseScreenshots {
+		for _, coordinates := range screenshot.IgnoreCoordinates {
+			ignored[*screenshot.ScreenshotName] = append(ignored[*screenshot.ScreenshotName], *coordinates)
+		}
+	}
+
+	// List baseline run screenshots
+	artifacts, err := selectedCanary.GetRunArtifacts(c.Context, run)
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmt.Sprintf("%-40s\t%s", "Screenshot", "Ignored areas"))
+	for _, artifact := range artifacts {
+		if artifact.Type != canary.ArtifactTypeScreenshot {
+			continue
+		}
+		name := artifact.Key[strings.LastIndex(artifact.Key, "/")+1:]
+		fmt.Println(fmt.Sprintf("%-40s\t%s", name, strings.Join(ignored[name], " ")))
+	}
+
+	return nil
+}
+
+func printFailures(c *cli.Context, canary *canary.Canary) error {
+	// Retrieve runs
+	filter, err := config.GetRunsFilter(c)
+	if err != nil {
+		return err
+	}
+	runs, err := canary.ListRuns(c.Context, filter)
+	if err != nil {
+		return err
+	}
+
+	// Search failed comparisons in runs reports
+	count := 0
+	for _, run := range runs {
+		report, err := canary.GetRunReport(c.Context, run)
+		if err != nil {
+			continue
+		}
+
+		for _, step := range report.GetSteps() {
+			for _, screenshot := range step.Screenshots {
+				if screenshot.IsVisualComparisonFailed() == false {
+					continue
+				}
+				if count == 0 {
+					fmt.Println(fmt.Sprintf("%-36s\t%-19s\t%-25s\t%-40s\t%s", "Run", "Started", "Step", "Screenshot", "Variance"))
+				}
+				fmt.Println(fmt.Sprintf("%-36s\t%-19s\t%-25s\t%-40s\t%s", *run.Id, run.Timeline.Started.Local().Format("2006-01-02 15:04:05"), step.Name, screenshot.FileName, screenshot.GetVisualVariance()))
+				count++
+			}
+		}
+	}
+
+	if count == 0 {
+		fmt.Println(fmt.Sprintf("No failed visual comparisons found in %d runs", len(runs)))
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package alarm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+)
+
+// Comparison operators short forms
+var comparisonOperators = map[string]string{
+	"<":  cloudwatch.ComparisonOperatorLessThanThreshold,
+	"<=": cloudwatch.ComparisonOperatorLessThanOrEqualToThreshold,
+	">":  cloudwatch.ComparisonOperatorGreaterThanThreshold,
+	">=": cloudwatch.ComparisonOperatorGreaterThanOrEqualToThreshold,
+}
+
+// Actions configuration
+type Actions struct {
+	Alarm            []string `yaml:"alarm" json:"alarm"`
+	OK               []string `yaml:"ok" json:"ok"`
+	InsufficientData []string `yaml:"insufficientData" json:"insufficientData"`
+}
+
+// Config configuration
+type Config struct {
+	Name              string  `yaml:"name" json:"name"`
+	Description       string  `yaml:"description" json:"description"`
+	Metric            string  `yaml:"metric" json:"metric"`
+	Step              string  `yaml:"step" json:"step"`
+	Statistic         string  `yaml:"statistic" json:"statistic"`
+	Comparison        string  `yaml:"comparison" json:"comparison"`
+	Threshold         float64 `yaml:"threshold" json:"threshold"`
+	Period            int64   `yaml:"period" json:"period"`
+	EvaluationPeriods int64   `yaml:"evaluationPeriods" json:"evaluationPeriods"`
+	DatapointsToAlarm int64   `yaml:"datapointsToAlarm" json:"datapointsToAlarm"`
+	MissingData       string  `yaml:"missingData" json:"missingData"`
+	Actions           Actions `yaml:"actions" json:"actions"`
+}
+
+// GetName return alarm name, metric name (and step name) are used when not provided
+func (c *Config) GetName() string {
+	if len(c.Name) > 0 {
+		return c.Name
+	}
+	if len(c.Step) > 0 {
+		return fmt.Sprintf("%s-%s", c.Step, c.Metric)
+	}
+	return c.Metric
+}
+
+// Validate check alarm configuration
+func (c *Config) Validate() error {
+	if len(c.Metric) == 0 {
+		return fmt.Errorf("Alarm %s: metric is required", c.GetName())
+	}
+	if _, err := c.getComparisonOperator(); err != nil {
+		return err
+	}
+	if c.Period != 0 && (c.Period < 10 || (c.Period > 30 && c.Period%60 != 0)) {
+		return fmt.Errorf("Alarm %s: period must be 10, 30 or a multiple of 60 seconds", c.GetName())
+	}
+	return nil
+}
+
+func (c *Config) getComparisonOperator() (string, error) {
+	if operator, ok := comparisonOperators[c.Comparison]; ok {
+		return operator, nil
+	}
+	for _, operator := range comparisonOperators {
+		if operator == c.Comparison {
+			return operator, nil
+		}
+	}
+	return "", fmt.Errorf("Alarm %s: comparison %s not supported, use <, <=, > or >=", c.GetName(), c.Comparison)
+}
+
+// Alarm structure
+type Alarm struct {
+	clients *awsinternal.Clients
+	config  Config
+
+	Name *string
+	Tags map[string]string
+}
+
+// New creates a new Alarm
+func New(clients *awsinternal.Clients, name *string, config Config) *Alarm {
+	return &Alarm{
+		clients: clients,
+		config:  config,
+
+		Name: name,
+	}
+}
+
+// Deploy create or update alarm on canary metric
+func (a *Alarm) Deploy(ctx context.Context, canaryName string) error {
+	comparisonOperator, err := a.config.getComparisonOperator()
+	if err != nil {
+		return err
+	}
+
+	// Elaborate metric dimensions
+	dimensions := []*cloudwatch.Dimension{
+		{
+			Name:  aws.String("CanaryName"),
+			Value: aws.String(canaryName),
+		},
+	}
+	if len(a.config.Step) > 0 {
+		dimensions = append(dimensions, &cloudwatch.Dimension{
+			Name:  aws.String("StepName"),
+			Value: aws.String(a.config.Step),
+		})
+	}
+
+	// Elaborate defaults
+	statistic := a.config.Statistic
+	if len(statistic) == 0 {
+		statistic = cloudwatch.StatisticAverage
+		if a.config.Metric == "Failed" {
+			statistic = cloudwatch.StatisticSum
+		}
+	}
+	period := a.config.Period
+	if period == 0 {
+		period = 300
+	}
+	evaluationPeriods := a.config.EvaluationPeriods
+	if evaluationPeriods == 0 {
+		evaluationPeriods = 1
+	}
+
+	input := &cloudwatch.PutMetricAlarmInput{
+		AlarmName:               a.Name,
+		Namespace:               aws.String(awsinternal.MetricsNamespace),
+		MetricName:              aws.String(a.config.Metric),
+		Dimensions:              dimensions,
+		Statistic:               aws.String(statistic),
+		ComparisonOperator:      aws.String(comparisonOperator),
+		Threshold:               aws.Float64(a.config.Threshold),
+		Period:                  aws.Int64(period),
+		EvaluationPeriods:       aws.Int64(evaluationPeriods),
+		ActionsEnabled:          aws.Bool(true),
+		AlarmActions:            aws.StringSlice(a.config.Actions.Alarm),
+		OKActions:               aws.StringSlice(a.config.Actions.OK),
+		InsufficientDataActions: aws.StringSlice(a.config.Actions.InsufficientData),
+	}
+	if len(a.config.Description) > 0 {
+		input.AlarmDescription = aws.String(a.config.Description)
+	}
+	if a.config.DatapointsToAlarm > 0 {
+		input.DatapointsToAlarm = aws.Int64(a.config.DatapointsToAlarm)
+	}
+	if len(a.config.MissingData) > 0 {
+		input.TreatMissingData = aws.String(a.config.MissingData)
+	}
+
+	_, err = a.clients.CloudWatch.PutMetricAlarmWithContext(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	// Tags are ignored when an existing alarm is put, apply them to the alarm ARN
+	return a.putTags(ctx)
+}
+
+func (a *Alarm) putTags(ctx context.Context) error {
+	if len(a.Tags) == 0 {
+		return nil
+	}
+
+	res, err := a.clients.CloudWatch.DescribeAlarmsWithContext(ctx, &cloudwatch.DescribeAlarmsInput{
+		AlarmNames: []*string{a.Name},
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.MetricAlarms) == 0 {
+		return fmt.Errorf("Alarm %s not found", *a.Name)
+	}
+
+	input := &cloudwatch.TagResourceInput{
+		ResourceARN: res.MetricAlarms[0].AlarmArn,
+	}
+	for key, value := range a.Tags {
+		input.Tags = append(input.Tags, &cloudwatch.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+	_, err = a.clients.CloudWatch.TagResourceWithContext(ctx, input)
+	return err
+}
+
+// Remove alarm
+func (a *Alarm) Remove(ctx context.Context) error {
+	_, err := a.clients.CloudWatch.DeleteAlarmsWithContext(ctx, &cloudwatch.DeleteAlarmsInput{
+		AlarmNames: []*string{a.Name},
+	})
+	return err
+}
+
+// ListNames return the names of alarms that start with prefix and watch a canary metric
+func ListNames(ctx context.Context, clients *awsinternal.Clients, prefix string, canaryName string) ([]string, error) {
+	names := []string{}
+
+	var nextToken *string
+	for {
+		res, err := clients.CloudWatch.DescribeAlarmsWithContext(ctx, &cloudwatch.DescribeAlarmsInput{
+			AlarmNamePrefix: aws.String(prefix),
+			AlarmTypes:      aws.StringSlice([]string{cloudwatch.AlarmTypeMetricAlarm}),
+			NextToken:       nextToken,
+		})
+		if err != nil {
+			return names, err
+		}
+
+		// Prefix can also match canaries with a longer name, check metric dimension
+		for _, alarm := range res.MetricAlarms {
+			if aws.StringValue(alarm.Namespace) == awsinternal.MetricsNamespace && hasCanaryDimension(alarm.Dimensions, canaryName) && strings.HasPrefix(*alarm.AlarmName, prefix) {
+				names = append(names, *alarm.AlarmName)
+			}
+		}
+
+		if res.NextToken == nil {
+			return names, nil
+		}
+		nextToken = res.NextToken
+	}
+}
+
+func hasCanaryDimension(dimensions []*cloudwatch.Dimension, canaryName string) bool {
+	for _, dimension := range dimensions {
+		if *dimension.Name == "CanaryName" && *dimension.Value == canaryName {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActionUsed check if an alarm that start with prefix still notify the provided action
+func IsActionUsed(ctx context.Context, clients *awsinternal.Clients, prefix string, action string) (bool, error) {
+	var nextToken *string
+	for {
+		res, err := clients.CloudWatch.DescribeAlarmsWithContext(ctx, &cloudwatch.DescribeAlarmsInput{
+			AlarmNamePrefix: aws.String(prefix),
+			AlarmTypes:      aws.StringSlice([]string{cloudwatch.AlarmTypeMetricAlarm}),
+			NextToken:       nextToken,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		for _, alarm := range res.MetricAlarms {
+			actions := []*string{}
+			actions = append(actions, alarm.AlarmActions...)
+			actions = append(actions, alarm.OKActions...)
+			actions = append(actions, alarm.InsufficientDataActions...)
+			for _, alarmAction := range actions {
+				if *alarmAction == action {
+					return true, nil
+				}
+			}
+		}
+
+		if res.NextToken == nil {
+			return false, nil
+		}
+		nextToken = res.NextToken
+	}
+}
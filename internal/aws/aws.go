@@ -4,11 +4,15 @@ import (
 	"os"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/urfave/cli/v2"
 )
 
+// MetricsNamespace is the CloudWatch namespace where canaries publish metrics
+const MetricsNamespace = "CloudWatchSynthetics"
+
 // NewAwsSession return a new AWS client session
 func NewAwsSession(c *cli.Context) *session.Session {
 	profile := c.String("profile")
@@ -20,6 +24,18 @@ func NewAwsSession(c *cli.Context) *session.Session {
 		awsConfig.Region = aws.String(region)
 	}
 
+	// Set custom endpoint, for example a local emulator
+	endpoint := c.String("endpoint-url")
+	if len(endpoint) != 0 {
+		awsConfig.Endpoint = aws.String(endpoint)
+	}
+	if c.Bool("s3-path-style") {
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+	}
+
+	// Set retry policy
+	request.WithRetryer(&awsConfig, NewRetryPolicy(c).Retryer())
+
 	// Check for debug mode
 	debugMode := os.Getenv("AWS_DEBUG")
 	if debugMode != "" {
@@ -35,13 +51,21 @@ func NewAwsSession(c *cli.Context) *session.Session {
 }
 
 // GetCallerAccountID return the account number
-func GetCallerAccountID(ses *session.Session) *string {
-	stsClient := sts.New(ses)
-	identity, _ := stsClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+func GetCallerAccountID(clients *Clients) *string {
+	identity, _ := clients.STS.GetCallerIdentity(&sts.GetCallerIdentityInput{})
 	return identity.Account
 }
 
+// GetCallerArn return the caller identity ARN
+func GetCallerArn(clients *Clients) (string, error) {
+	identity, err := clients.STS.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return *identity.Arn, nil
+}
+
 // GetCallerRegion return the account number
-func GetCallerRegion(ses *session.Session) *string {
-	return ses.Config.Region
+func GetCallerRegion(clients *Clients) *string {
+	return clients.Region
 }
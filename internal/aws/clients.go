@@ -0,0 +1,67 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/aws/aws-sdk-go/service/synthetics/syntheticsiface"
+	"github.com/urfave/cli/v2"
+)
+
+// Clients structure
+type Clients struct {
+	Region *string
+
+	Synthetics       syntheticsiface.SyntheticsAPI
+	SyntheticsDryRun SyntheticsDryRunAPI
+	S3               s3iface.S3API
+	S3Uploader       s3manageriface.UploaderAPI
+	Lambda           lambdaiface.LambdaAPI
+	IAM              iamiface.IAMAPI
+	STS              stsiface.STSAPI
+	CloudWatch       cloudwatchiface.CloudWatchAPI
+	CloudWatchLogs   cloudwatchlogsiface.CloudWatchLogsAPI
+	SNS              snsiface.SNSAPI
+}
+
+// NewClients creates AWS services clients from session, endpoints can be overridden by service name
+func NewClients(ses *session.Session, endpoints map[string]string) *Clients {
+	s3Client := s3.New(ses, serviceConfig(endpoints, "s3"))
+	syntheticsClient := synthetics.New(ses, serviceConfig(endpoints, "synthetics"))
+
+	return &Clients{
+		Region: ses.Config.Region,
+
+		Synthetics:       syntheticsClient,
+		SyntheticsDryRun: NewSyntheticsDryRun(syntheticsClient),
+		S3:               s3Client,
+		S3Uploader:       s3manager.NewUploaderWithClient(s3Client),
+		Lambda:           lambda.New(ses, serviceConfig(endpoints, "lambda")),
+		IAM:              iam.New(ses, serviceConfig(endpoints, "iam")),
+		STS:              sts.New(ses, serviceConfig(endpoints, "sts")),
+		CloudWatch:       cloudwatch.New(ses, serviceConfig(endpoints, "cloudwatch")),
+		CloudWatchLogs:   cloudwatchlogs.New(ses, serviceConfig(endpoints, "logs")),
+		SNS:              sns.New(ses, serviceConfig(endpoints, "sns")),
+	}
+}
+
+// NewAwsClients return AWS services clients configured by user input,
+// it can be replaced in order to run commands against a different backend
+var NewAwsClients = func(c *cli.Context) *Clients {
+	return NewClients(NewAwsSession(c), GetServiceEndpoints(c))
+}
@@ -0,0 +1,95 @@
+package aws
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+)
+
+// SyntheticsDryRunAPI is the Synthetics canaries dry runs API, not modelled by the AWS SDK version used by this CLI
+type SyntheticsDryRunAPI interface {
+	StartCanaryDryRunWithContext(ctx aws.Context, input *StartCanaryDryRunInput, opts ...request.Option) (*StartCanaryDryRunOutput, error)
+	GetCanaryDryRunRunsWithContext(ctx aws.Context, input *GetCanaryDryRunRunsInput, opts ...request.Option) (*synthetics.GetCanaryRunsOutput, error)
+}
+
+// StartCanaryDryRunInput structure, configuration tested by the dry run of an existing canary
+type StartCanaryDryRunInput struct {
+	_ struct{} `type:"structure"`
+
+	Name                         *string                           `location:"uri" locationName:"name" min:"1" type:"string" required:"true"`
+	ArtifactConfig               *synthetics.ArtifactConfigInput_  `type:"structure"`
+	ArtifactS3Location           *string                           `min:"1" type:"string"`
+	Code                         *synthetics.CanaryCodeInput       `type:"structure"`
+	ExecutionRoleArn             *string                           `min:"1" type:"string"`
+	FailureRetentionPeriodInDays *int64                            `min:"1" type:"integer"`
+	RunConfig                    *synthetics.CanaryRunConfigInput  `type:"structure"`
+	RuntimeVersion               *string                           `min:"1" type:"string"`
+	SuccessRetentionPeriodInDays *int64                            `min:"1" type:"integer"`
+	VisualReference              *synthetics.VisualReferenceInput_ `type:"structure"`
+	VpcConfig                    *synthetics.VpcConfigInput        `type:"structure"`
+}
+
+// StartCanaryDryRunOutput structure
+type StartCanaryDryRunOutput struct {
+	_ struct{} `type:"structure"`
+
+	DryRunConfig *DryRunConfigOutput `type:"structure"`
+}
+
+// DryRunConfigOutput structure, the started dry run
+type DryRunConfigOutput struct {
+	_ struct{} `type:"structure"`
+
+	DryRunId     *string    `type:"string"`
+	LastModified *time.Time `type:"timestamp"`
+}
+
+// GetCanaryDryRunRunsInput structure, runs of a canary dry run
+type GetCanaryDryRunRunsInput struct {
+	_ struct{} `type:"structure"`
+
+	Name       *string `location:"uri" locationName:"name" min:"1" type:"string" required:"true"`
+	DryRunId   *string `type:"string"`
+	MaxResults *int64  `min:"1" type:"integer"`
+	NextToken  *string `min:"4" type:"string"`
+}
+
+// syntheticsDryRun send dry runs operations with the Synthetics client protocol, endpoint and signer
+type syntheticsDryRun struct {
+	client *synthetics.Synthetics
+}
+
+// NewSyntheticsDryRun return the dry runs API backed by a Synthetics client
+func NewSyntheticsDryRun(client *synthetics.Synthetics) SyntheticsDryRunAPI {
+	return &syntheticsDryRun{
+		client: client,
+	}
+}
+
+// StartCanaryDryRunWithContext start a dry run of an existing canary with the provided configuration
+func (s *syntheticsDryRun) StartCanaryDryRunWithContext(ctx aws.Context, input *StartCanaryDryRunInput, opts ...request.Option) (*StartCanaryDryRunOutput, error) {
+	output := &StartCanaryDryRunOutput{}
+	req := s.client.NewRequest(&request.Operation{
+		Name:       "StartCanaryDryRun",
+		HTTPMethod: "POST",
+		HTTPPath:   "/canary/{name}/dry-run/start",
+	}, input, output)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return output, req.Send()
+}
+
+// GetCanaryDryRunRunsWithContext return runs of a canary dry run, newest first
+func (s *syntheticsDryRun) GetCanaryDryRunRunsWithContext(ctx aws.Context, input *GetCanaryDryRunRunsInput, opts ...request.Option) (*synthetics.GetCanaryRunsOutput, error) {
+	output := &synthetics.GetCanaryRunsOutput{}
+	req := s.client.NewRequest(&request.Operation{
+		Name:       "GetCanaryRuns",
+		HTTPMethod: "POST",
+		HTTPPath:   "/canary/{name}/runs",
+	}, input, output)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return output, req.Send()
+}
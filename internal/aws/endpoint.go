@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/urfave/cli/v2"
+)
+
+// Services whose endpoint can be overridden, mapped to their environment variable suffix
+var endpointServices = map[string]string{
+	"synthetics": "SYNTHETICS",
+	"s3":         "S3",
+	"iam":        "IAM",
+	"lambda":     "LAMBDA",
+	"sts":        "STS",
+	"cloudwatch": "CLOUDWATCH",
+	"logs":       "CLOUDWATCH_LOGS",
+	"sns":        "SNS",
+}
+
+// GetServiceEndpoints return endpoints overrides by service name, loaded from
+// AWS_ENDPOINT_URL_<SERVICE> environment variables and "service=url" user inputs
+func GetServiceEndpoints(c *cli.Context) map[string]string {
+	endpoints := map[string]string{}
+
+	// Load from environment variables
+	for service, suffix := range endpointServices {
+		endpoint := os.Getenv("AWS_ENDPOINT_URL_" + suffix)
+		if len(endpoint) > 0 {
+			endpoints[service] = endpoint
+		}
+	}
+
+	// Load from user input
+	for _, override := range c.StringSlice("service-endpoint-url") {
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 || len(parts[1]) == 0 {
+			fmt.Fprintln(os.Stderr, fmt.Sprintf("Ignoring service endpoint %s, expected format is service=url", override))
+			continue
+		}
+		service := strings.ToLower(parts[0])
+		if _, ok := endpointServices[service]; !ok {
+			fmt.Fprintln(os.Stderr, fmt.Sprintf("Ignoring service endpoint %s, unknown service %s", override, service))
+			continue
+		}
+		endpoints[service] = parts[1]
+	}
+
+	return endpoints
+}
+
+// serviceConfig return the client configuration for a service
+func serviceConfig(endpoints map[string]string, service string) *aws.Config {
+	config := aws.NewConfig()
+	if endpoint, ok := endpoints[service]; ok {
+		config.Endpoint = aws.String(endpoint)
+	}
+	return config
+}
@@ -0,0 +1,165 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/urfave/cli/v2"
+)
+
+// Errors codes that are always safe to retry
+var retryableErrorCodes = map[string]bool{
+	"TooManyRequestsException": true, // Synthetics, Lambda
+	"ThrottlingException":      true,
+	"Throttling":               true, // IAM
+	"RequestLimitExceeded":     true,
+	"SlowDown":                 true, // S3
+	"OperationAborted":         true, // S3
+	"InternalServerException":  true, // Synthetics
+	"InternalFailure":          true,
+	"ServiceFailure":           true, // IAM
+	"ServiceException":         true, // Lambda
+	"ServiceUnavailable":       true,
+	"ConcurrentModification":   true, // IAM
+}
+
+// Conflicts caused by a canary still in a transitional state
+var transitionalStates = []string{
+	"CREATING",
+	"UPDATING",
+	"STARTING",
+	"STOPPING",
+	"DELETING",
+}
+
+// RetryPolicy structure
+type RetryPolicy struct {
+	MaxRetries int
+	MinDelay   time.Duration
+	MaxDelay   time.Duration
+}
+
+// NewRetryPolicy return the retry policy configured by user input
+func NewRetryPolicy(c *cli.Context) *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: c.Int("max-retries"),
+		MinDelay:   1000 * time.Millisecond,
+		MaxDelay:   30 * 1000 * time.Millisecond,
+	}
+}
+
+// Delay return the exponential backoff, with jitter, to wait before the provided retry attempt
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	delay := float64(p.MinDelay) * math.Pow(2, float64(attempt))
+	if delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	// Wait at least half of the delay, randomize the other half
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// RoleNotAssumableError is returned when a resource ends in error state because its role is not yet usable
+type RoleNotAssumableError struct {
+	Reason string
+}
+
+func (e *RoleNotAssumableError) Error() string {
+	return e.Reason
+}
+
+// Do execute fn until it succeed, returns an error other than RoleNotAssumableError, retries are exhausted or context is done.
+// API errors are already retried by the SDK retryer, only asynchronous failures must be retried here
+func (p *RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	var err error
+	var roleErr *RoleNotAssumableError
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || errors.As(err, &roleErr) == false || attempt >= p.MaxRetries {
+			return err
+		}
+
+		err = aws.SleepWithContext(ctx, p.Delay(attempt))
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Retryer return an AWS SDK retryer that use this policy
+func (p *RetryPolicy) Retryer() request.Retryer {
+	return &retryer{
+		DefaultRetryer: client.DefaultRetryer{
+			NumMaxRetries: p.MaxRetries,
+		},
+		policy: p,
+	}
+}
+
+type retryer struct {
+	client.DefaultRetryer
+	policy *RetryPolicy
+}
+
+// ShouldRetry check if the request error can be retried
+func (r *retryer) ShouldRetry(req *request.Request) bool {
+	if IsRetryableError(req.Error) {
+		return true
+	}
+	return r.DefaultRetryer.ShouldRetry(req)
+}
+
+// RetryRules return the delay before retrying the request
+func (r *retryer) RetryRules(req *request.Request) time.Duration {
+	return r.policy.Delay(req.RetryCount)
+}
+
+// IsRetryableError check if error is a transient Synthetics, IAM, Lambda or S3 error
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// Check for role not yet propagated
+	if IsRoleNotAssumableError(err.Error()) {
+		return true
+	}
+
+	// Check error code
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	if retryableErrorCodes[awsErr.Code()] {
+		return true
+	}
+
+	// Check for transitional canary state
+	if awsErr.Code() == "ConflictException" {
+		for _, state := range transitionalStates {
+			if strings.Contains(strings.ToUpper(awsErr.Message()), state) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsRoleNotAssumableError check if message report an IAM role not yet usable by Lambda
+func IsRoleNotAssumableError(message string) bool {
+	return strings.Contains(message, "cannot be assumed")
+}
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
@@ -0,0 +1,45 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestRetryPolicyDo(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, 1},
+		{"role not assumable", &RoleNotAssumableError{Reason: "The role cannot be assumed by Lambda"}, 3},
+		{"throttling already retried by sdk", awserr.New("ThrottlingException", "Rate exceeded", nil), 1},
+		{"generic error", errors.New("boom"), 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := &RetryPolicy{
+				MaxRetries: 2,
+				MinDelay:   time.Millisecond,
+				MaxDelay:   time.Millisecond,
+			}
+
+			calls := 0
+			err := policy.Do(context.Background(), func() error {
+				calls++
+				return test.err
+			})
+			if err != test.err {
+				t.Errorf("expected error %v, got %v", test.err, err)
+			}
+			if calls != test.expected {
+				t.Errorf("expected %d calls, got %d", test.expected, calls)
+			}
+		})
+	}
+}
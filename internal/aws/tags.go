@@ -0,0 +1,32 @@
+package aws
+
+// TagPrefix is the prefix of tags set by the CLI, tags with this prefix are never removed
+const TagPrefix = "aws-canary:"
+
+// ManagedTag is the tag set on resources created by the CLI
+const ManagedTag = TagPrefix + "managed"
+
+// ProjectTag is the tag with the project name of resources created by the CLI
+const ProjectTag = TagPrefix + "project"
+
+// ConfigPathTag is the tag with the configuration file path of resources created by the CLI
+const ConfigPathTag = TagPrefix + "config-path"
+
+// GetManagedTags return tags to set on resources created by the CLI, empty values are omitted
+func GetManagedTags(project string, configPath string) map[string]string {
+	tags := map[string]string{
+		ManagedTag: "true",
+	}
+	if len(project) > 0 {
+		tags[ProjectTag] = project
+	}
+	if len(configPath) > 0 {
+		tags[ConfigPathTag] = configPath
+	}
+	return tags
+}
+
+// IsManaged check if resource tags are set by the CLI
+func IsManaged(tags map[string]string) bool {
+	return tags[ManagedTag] == "true"
+}
@@ -1,62 +1,66 @@
 package bucket
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
 )
 
-type clients struct {
-	s3 *s3.S3
-}
-
 // Bucket structure
 type Bucket struct {
-	clients *clients
+	clients *awsinternal.Clients
 
 	Name     *string
 	Location *string
+	Tags     map[string]string
 }
 
 // New creates a bucket
-func New(ses *session.Session, name *string) *Bucket {
+func New(clients *awsinternal.Clients, name *string) *Bucket {
 	location := fmt.Sprintf("s3://%s", *name)
 
 	return &Bucket{
-		clients: &clients{
-			s3.New(ses),
-		},
+		clients:  clients,
 		Name:     name,
 		Location: &location,
 	}
 }
 
 // IsDeployed check if IAM Bucket name is present in current AWS account
-func (b *Bucket) IsDeployed() bool {
-	_, err := b.clients.s3.ListObjectsV2(&s3.ListObjectsV2Input{
+func (b *Bucket) IsDeployed(ctx context.Context) bool {
+	_, err := b.clients.S3.ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
 		Bucket: b.Name,
 	})
 	return err == nil
 }
 
 // Deploy Bucket
-func (b *Bucket) Deploy() error {
+func (b *Bucket) Deploy(ctx context.Context) error {
 
 	// Check if bucket is already deployed
-	if b.IsDeployed() == false {
+	if b.IsDeployed(ctx) == false {
 		// Create new Bucket
-		_, err := b.clients.s3.CreateBucket(&s3.CreateBucketInput{
+		_, err := b.clients.S3.CreateBucketWithContext(ctx, &s3.CreateBucketInput{
 			Bucket: b.Name,
 		})
 		if err != nil {
 			return err
 		}
+
+	}
+
+	// Tag bucket, current tags are preserved
+	err := b.putTags(ctx)
+	if err != nil {
+		return err
 	}
 
 	// Put public ACL lock
-	_, err := b.clients.s3.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+	_, err = b.clients.S3.PutPublicAccessBlockWithContext(ctx, &s3.PutPublicAccessBlockInput{
 		Bucket: b.Name,
 		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
 			BlockPublicAcls:       aws.Bool(true),
@@ -69,9 +73,68 @@ func (b *Bucket) Deploy() error {
 	return err
 }
 
+func (b *Bucket) putTags(ctx context.Context) error {
+	if len(b.Tags) == 0 {
+		return nil
+	}
+
+	// Merge missing tags with the current ones, bucket tagging replace the whole set.
+	// Buckets are shared between projects so existing values are never overwritten
+	tags, err := b.GetTags(ctx)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for key, value := range b.Tags {
+		if _, ok := tags[key]; !ok {
+			tags[key] = value
+			changed = true
+		}
+	}
+	if changed == false {
+		return nil
+	}
+
+	tagSet := []*s3.Tag{}
+	for key, value := range tags {
+		tagSet = append(tagSet, &s3.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+
+	_, err = b.clients.S3.PutBucketTaggingWithContext(ctx, &s3.PutBucketTaggingInput{
+		Bucket: b.Name,
+		Tagging: &s3.Tagging{
+			TagSet: tagSet,
+		},
+	})
+	return err
+}
+
+// GetTags return current bucket tags
+func (b *Bucket) GetTags(ctx context.Context) (map[string]string, error) {
+	tags := map[string]string{}
+
+	res, err := b.clients.S3.GetBucketTaggingWithContext(ctx, &s3.GetBucketTaggingInput{
+		Bucket: b.Name,
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchTagSet" {
+			return tags, nil
+		}
+		return tags, err
+	}
+
+	for _, tag := range res.TagSet {
+		tags[*tag.Key] = *tag.Value
+	}
+	return tags, nil
+}
+
 // DeployLifecycleConfigurationExpires deploy lifecycle configuration for expiration
-func (b *Bucket) DeployLifecycleConfigurationExpires(days int64) error {
-	_, err := b.clients.s3.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+func (b *Bucket) DeployLifecycleConfigurationExpires(ctx context.Context, days int64) error {
+	_, err := b.clients.S3.PutBucketLifecycleConfigurationWithContext(ctx, &s3.PutBucketLifecycleConfigurationInput{
 		Bucket: b.Name,
 		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
 			Rules: []*s3.LifecycleRule{
@@ -89,19 +152,25 @@ func (b *Bucket) DeployLifecycleConfigurationExpires(days int64) error {
 }
 
 // Empty Bucket
-func (b *Bucket) Empty() error {
+func (b *Bucket) Empty(ctx context.Context) error {
+	return b.EmptyPrefix(ctx, "")
+}
+
+// EmptyPrefix delete Bucket objects whose key starts with prefix
+func (b *Bucket) EmptyPrefix(ctx context.Context, prefix string) error {
 
 	// Check if bucket is not deployed
-	if b.IsDeployed() == false {
+	if b.IsDeployed(ctx) == false {
 		return nil
 	}
 
-	var listRes *s3.ListObjectsV2Output
+	var continuationToken *string
 	for {
 		// List objects
-		listRes, err := b.clients.s3.ListObjectsV2(&s3.ListObjectsV2Input{
+		listRes, err := b.clients.S3.ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
 			Bucket:            b.Name,
-			ContinuationToken: listRes.ContinuationToken,
+			Prefix:            aws.String(prefix),
+			ContinuationToken: continuationToken,
 		})
 		if err != nil {
 			return err
@@ -116,32 +185,38 @@ func (b *Bucket) Empty() error {
 		}
 
 		// Delete objects
-		b.clients.s3.DeleteObjects(&s3.DeleteObjectsInput{
-			Bucket: b.Name,
-			Delete: &s3.Delete{
-				Objects: keysToDelete,
-			},
-		})
+		if len(keysToDelete) > 0 {
+			_, err = b.clients.S3.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+				Bucket: b.Name,
+				Delete: &s3.Delete{
+					Objects: keysToDelete,
+				},
+			})
+			if err != nil {
+				return err
+			}
+		}
 
-		// Check if list is compleated
-		if *listRes.IsTruncated {
+		// Check if list is completed
+		if aws.BoolValue(listRes.IsTruncated) == false {
 			break
 		}
+		continuationToken = listRes.NextContinuationToken
 	}
 
 	return nil
 }
 
 // Remove Bucket
-func (b *Bucket) Remove() error {
+func (b *Bucket) Remove(ctx context.Context) error {
 
 	// Check if bucket is not deployed
-	if b.IsDeployed() == false {
+	if b.IsDeployed(ctx) == false {
 		return nil
 	}
 
 	// Delete Bucket
-	_, err := b.clients.s3.DeleteBucket(&s3.DeleteBucketInput{
+	_, err := b.clients.S3.DeleteBucketWithContext(ctx, &s3.DeleteBucketInput{
 		Bucket: b.Name,
 	})
 
@@ -0,0 +1,138 @@
+package canary
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/synthetics"
+)
+
+// Artifact types
+const (
+	ArtifactTypeScreenshot = "screenshots"
+	ArtifactTypeHar        = "har"
+	ArtifactTypeReport     = "report"
+	ArtifactTypeLog        = "logs"
+	ArtifactTypeOther      = "other"
+)
+
+// ArtifactTypes contains the types that can be used as filter
+var ArtifactTypes = []string{
+	ArtifactTypeScreenshot,
+	ArtifactTypeHar,
+	ArtifactTypeReport,
+	ArtifactTypeLog,
+}
+
+// Artifact structure
+type Artifact struct {
+	Bucket string
+	Key    string
+	Size   int64
+	Type   string
+}
+
+// GetRun return canary run by id
+func (c *Canary) GetRun(ctx context.Context, id string) (*synthetics.CanaryRun, error) {
+	var found *synthetics.CanaryRun
+	err := c.GetRunsPages(ctx, func(runs []*synthetics.CanaryRun) bool {
+		for _, run := range runs {
+			if *run.Id == id {
+				found = run
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if run was found
+	if found == nil {
+		return nil, fmt.Errorf("Run %s not found for canary %s", id, c.Name)
+	}
+
+	return found, nil
+}
+
+// GetRunArtifacts return all artifacts of a canary run
+func (c *Canary) GetRunArtifacts(ctx context.Context, run *synthetics.CanaryRun) ([]*Artifact, error) {
+	artifacts := []*Artifact{}
+
+	// Check if not data are set
+	if *run.ArtifactS3Location == "No data" {
+		return artifacts, nil
+	}
+
+	// List artifact objects
+	bucketName, objects, err := c.listRunArtifacts(ctx, run)
+	if err != nil {
+		return artifacts, err
+	}
+
+	for _, object := range objects {
+		artifacts = append(artifacts, &Artifact{
+			Bucket: bucketName,
+			Key:    *object.Key,
+			Size:   *object.Size,
+			Type:   GetArtifactType(*object.Key),
+		})
+	}
+
+	return artifacts, nil
+}
+
+// DownloadArtifact save artifact content into local file
+func (c *Canary) DownloadArtifact(ctx context.Context, artifact *Artifact, filePath string) error {
+	content, err := c.getArtifact(ctx, artifact.Bucket, artifact.Key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(path.Dir(filePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filePath, content, 0644)
+}
+
+// GetArtifactPath return the local path of an artifact inside the output directory,
+// keys that would be saved outside of it are rejected
+func GetArtifactPath(outDir string, key string) (string, error) {
+	cleanKey := path.Clean(key)
+	if cleanKey == "." || path.IsAbs(cleanKey) || cleanKey == ".." || strings.HasPrefix(cleanKey, "../") {
+		return "", fmt.Errorf("Artifact key %s is not a valid path", key)
+	}
+
+	// Check that the resulting path is inside output directory
+	filePath := filepath.Join(outDir, filepath.FromSlash(cleanKey))
+	relPath, err := filepath.Rel(filepath.Clean(outDir), filePath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("Artifact key %s is not a valid path", key)
+	}
+
+	return filePath, nil
+}
+
+// GetArtifactType return artifact type by object key
+func GetArtifactType(key string) string {
+	name := strings.ToLower(path.Base(key))
+	switch {
+	case strings.HasPrefix(name, "syntheticsreport") && path.Ext(name) == ".json":
+		return ArtifactTypeReport
+	case strings.HasSuffix(name, ".har") || strings.HasSuffix(name, ".har.html") || strings.HasSuffix(name, ".har.json"):
+		return ArtifactTypeHar
+	case path.Ext(name) == ".png" || path.Ext(name) == ".jpg" || path.Ext(name) == ".jpeg":
+		return ArtifactTypeScreenshot
+	case path.Ext(name) == ".txt" || path.Ext(name) == ".log":
+		return ArtifactTypeLog
+	}
+	return ArtifactTypeOther
+}
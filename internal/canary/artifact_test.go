@@ -0,0 +1,40 @@
+package canary
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetArtifactPath(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+		fail     bool
+	}{
+		{key: "canary/us-east-1/test/2021/03/01/log.txt", expected: "out/canary/us-east-1/test/2021/03/01/log.txt"},
+		{key: "canary/test/./screenshots/../log.txt", expected: "out/canary/test/log.txt"},
+		{key: "canary/test/../../../evil.txt", fail: true},
+		{key: "../evil.txt", fail: true},
+		{key: "/etc/passwd", fail: true},
+		{key: "..", fail: true},
+		{key: "", fail: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			filePath, err := GetArtifactPath("out", test.key)
+			if test.fail {
+				if err == nil {
+					t.Errorf("expected key %s to be rejected, got %s", test.key, filePath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if filePath != filepath.FromSlash(test.expected) {
+				t.Errorf("expected %s, found %s", test.expected, filePath)
+			}
+		})
+	}
+}
@@ -2,28 +2,27 @@ package canary
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
-	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/internal/alarm"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/dashboard"
 	"github.com/daaru00/aws-canary-cli/internal/iam"
+	"github.com/daaru00/aws-canary-cli/internal/topic"
 )
 
-type clients struct {
-	synthetics *synthetics.Synthetics
-	s3         *s3.S3
-	s3uploader *s3manager.Uploader
-	lambda     *lambda.Lambda
-	sts        *sts.STS
-}
-
 // Schedule configuration
 type Schedule struct {
 	DurationInSeconds int64  `yaml:"duration" json:"duration"`
@@ -42,12 +41,54 @@ type RetentionConfig struct {
 	SuccessRetentionPeriod int64 `yaml:"success" json:"success"`
 }
 
+// EncryptionConfig configuration
+type EncryptionConfig struct {
+	Mode   string `yaml:"mode" json:"mode"`
+	KmsKey string `yaml:"kmsKey" json:"kmsKey"`
+}
+
+// ArtifactConfig configuration
+type ArtifactConfig struct {
+	Encryption EncryptionConfig `yaml:"encryption" json:"encryption"`
+}
+
+// Validate check artifacts configuration
+func (c *ArtifactConfig) Validate() error {
+	switch c.Encryption.Mode {
+	case "", synthetics.EncryptionModeSseS3:
+		if len(c.Encryption.KmsKey) > 0 {
+			return fmt.Errorf("Artifacts encryption KMS key requires %s mode", synthetics.EncryptionModeSseKms)
+		}
+	case synthetics.EncryptionModeSseKms:
+		if len(c.Encryption.KmsKey) == 0 {
+			return fmt.Errorf("Artifacts encryption mode %s requires a KMS key ARN", synthetics.EncryptionModeSseKms)
+		}
+	default:
+		return fmt.Errorf("Artifacts encryption mode %s not supported, use %s or %s", c.Encryption.Mode, synthetics.EncryptionModeSseS3, synthetics.EncryptionModeSseKms)
+	}
+	return nil
+}
+
+// GetKmsKey return the KMS key used to encrypt artifacts, empty when not encrypted with KMS
+func (c *ArtifactConfig) GetKmsKey() string {
+	if c.Encryption.Mode != synthetics.EncryptionModeSseKms {
+		return ""
+	}
+	return c.Encryption.KmsKey
+}
+
+// DefaultPollInterval is the interval between canary status checks, runs and logs are polled half as often
+var DefaultPollInterval = 1000 * time.Millisecond
+
 // Canary structure
 type Canary struct {
-	clients *clients
-	region  *string
+	clients      *awsinternal.Clients
+	region       *string
+	output       io.Writer
+	pollInterval time.Duration
 
 	Name                 string               `yaml:"name" json:"name"`
+	Project              string               `yaml:"project" json:"project"`
 	Retention            RetentionConfig      `yaml:"retention" json:"retention"`
 	RuntimeVersion       string               `yaml:"runtime" json:"runtime"`
 	Tags                 map[string]string    `yaml:"tags" json:"tags"`
@@ -60,21 +101,23 @@ type Canary struct {
 	VpcConfig            VpcConfig            `yaml:"vpc" json:"vpc"`
 	RoleName             string               `yaml:"role" json:"role"`
 	PolicyStatements     []iam.StatementEntry `yaml:"policies" json:"policies"`
+	Alarms               []alarm.Config       `yaml:"alarms" json:"alarms"`
+	Notifications        *topic.Config        `yaml:"notifications" json:"notifications"`
+	Dashboard            dashboard.Config     `yaml:"dashboard" json:"dashboard"`
+	Groups               []string             `yaml:"groups" json:"groups"`
+	ArtifactConfig       ArtifactConfig       `yaml:"artifacts" json:"artifacts"`
+	Visual               *VisualConfig        `yaml:"visual" json:"visual"`
+	ConfigPath           string               `yaml:"-" json:"-"`
+	ConfigRoot           string               `yaml:"-" json:"-"`
 }
 
 // New creates a new Canary
-func New(ses *session.Session, name string) *Canary {
-	clients := &clients{
-		synthetics: synthetics.New(ses),
-		s3:         s3.New(ses),
-		s3uploader: s3manager.NewUploader(ses),
-		lambda:     lambda.New(ses),
-		sts:        sts.New(ses),
-	}
-
+func New(clients *awsinternal.Clients, name string) *Canary {
 	return &Canary{
-		clients: clients,
-		region:  ses.Config.Region,
+		clients:      clients,
+		region:       clients.Region,
+		output:       os.Stdout,
+		pollInterval: DefaultPollInterval,
 
 		Name:           name,
 		RuntimeVersion: "syn-nodejs-puppeteer-3.9",
@@ -99,6 +142,16 @@ func New(ses *session.Session, name string) *Canary {
 	}
 }
 
+// SetOutput set the writer used to print canary messages
+func (c *Canary) SetOutput(output io.Writer) {
+	c.output = output
+}
+
+// Logf print a message prefixed with canary name
+func (c *Canary) Logf(format string, a ...interface{}) {
+	fmt.Fprintln(c.output, fmt.Sprintf("[%s] %s", c.Name, fmt.Sprintf(format, a...)))
+}
+
 // GetFlatTags return tags as flat string
 func (c *Canary) GetFlatTags(separator string) *string {
 	flat := ""
@@ -117,48 +170,53 @@ func (c *Canary) GetFlatTags(separator string) *string {
 	return &flat
 }
 
+// ListDeployed return all canaries deployed in current AWS account and region
+func ListDeployed(ctx context.Context, clients *awsinternal.Clients) ([]*synthetics.Canary, error) {
+	canaries := []*synthetics.Canary{}
+
+	var nextToken *string
+	for {
+		res, err := clients.Synthetics.DescribeCanariesWithContext(ctx, &synthetics.DescribeCanariesInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return canaries, err
+		}
+
+		canaries = append(canaries, res.Canaries...)
+
+		if res.NextToken == nil {
+			return canaries, nil
+		}
+		nextToken = res.NextToken
+	}
+}
+
+// GetArn return canary ARN
+func (c *Canary) GetArn(region *string, account *string) string {
+	return fmt.Sprintf("arn:aws:synthetics:%s:%s:canary:%s", *region, *account, c.Name)
+}
+
 // IsDeployed check if Canary name is present in current AWS account
-func (c *Canary) IsDeployed() bool {
-	_, err := c.clients.synthetics.GetCanary(&synthetics.GetCanaryInput{
+func (c *Canary) IsDeployed(ctx context.Context) bool {
+	_, err := c.clients.Synthetics.GetCanaryWithContext(ctx, &synthetics.GetCanaryInput{
 		Name: &c.Name,
 	})
 	return err == nil
 }
 
 // Deploy canary
-func (c *Canary) Deploy(role *iam.Role, artifactBucketLocation *string) error {
+func (c *Canary) Deploy(ctx context.Context, role *iam.Role, artifactBucketLocation *string) error {
 	var err error
 
 	// Elaborate code config
-	var codeInputConfig *synthetics.CanaryCodeInput
-	if len(c.Code.archives3bucket) != 0 && len(c.Code.archives3key) != 0 {
-		// Set S3 path for code
-		codeInputConfig = &synthetics.CanaryCodeInput{
-			Handler:  &c.Code.Handler,
-			S3Bucket: &c.Code.archives3bucket,
-			S3Key:    &c.Code.archives3key,
-		}
-	} else {
-		// Load archive path
-		data, err := c.Code.ReadArchive()
-		if err != nil {
-			return err
-		}
-
-		// Set zip file code
-		codeInputConfig = &synthetics.CanaryCodeInput{
-			Handler: &c.Code.Handler,
-			ZipFile: data,
-		}
+	codeInputConfig, err := c.getCodeInput()
+	if err != nil {
+		return err
 	}
 
 	// Elaborate run config
-	runConfig := &synthetics.CanaryRunConfigInput{
-		ActiveTracing:        &c.ActiveTracing,
-		EnvironmentVariables: aws.StringMap(c.EnvironmentVariables),
-		MemoryInMB:           &c.MemoryInMB,
-		TimeoutInSeconds:     &c.TimeoutInSeconds,
-	}
+	runConfig := c.getRunConfigInput()
 
 	// Elaborate schedule config
 	scheduleConfig := &synthetics.CanaryScheduleInput{
@@ -167,16 +225,13 @@ func (c *Canary) Deploy(role *iam.Role, artifactBucketLocation *string) error {
 	}
 
 	// Elaborate VPC configs
-	var vpcConfig *synthetics.VpcConfigInput
-	if c.HasVpcConfig() {
-		vpcConfig = &synthetics.VpcConfigInput{
-			SecurityGroupIds: aws.StringSlice(c.VpcConfig.SecurityGroupIds),
-			SubnetIds:        aws.StringSlice(c.VpcConfig.SubnetIDs),
-		}
-	}
+	vpcConfig := c.getVpcConfigInput()
+
+	// Elaborate artifacts config, when not set current one is preserved
+	artifactConfig := c.getArtifactConfigInput()
 
 	// Check if Canary is already deployed
-	if c.IsDeployed() == false {
+	if c.IsDeployed(ctx) == false {
 		input := &synthetics.CreateCanaryInput{
 			Name:                         &c.Name,
 			ArtifactS3Location:           artifactBucketLocation,
@@ -187,7 +242,8 @@ func (c *Canary) Deploy(role *iam.Role, artifactBucketLocation *string) error {
 			RuntimeVersion:               &c.RuntimeVersion,
 			Schedule:                     scheduleConfig,
 			Code:                         codeInputConfig,
-			Tags:                         aws.StringMap(c.Tags),
+			Tags:                         aws.StringMap(c.GetDeployTags()),
+			ArtifactConfig:               artifactConfig,
 		}
 
 		// Setup VPc config only if set
@@ -196,7 +252,7 @@ func (c *Canary) Deploy(role *iam.Role, artifactBucketLocation *string) error {
 		}
 
 		// Create canary
-		_, err = c.clients.synthetics.CreateCanary(input)
+		_, err = c.clients.Synthetics.CreateCanaryWithContext(ctx, input)
 	} else {
 		input := &synthetics.UpdateCanaryInput{
 			Name:                         &c.Name,
@@ -207,6 +263,7 @@ func (c *Canary) Deploy(role *iam.Role, artifactBucketLocation *string) error {
 			RuntimeVersion:               &c.RuntimeVersion,
 			Schedule:                     scheduleConfig,
 			Code:                         codeInputConfig,
+			ArtifactConfig:               artifactConfig,
 		}
 
 		// Setup VPc config only if set
@@ -214,34 +271,147 @@ func (c *Canary) Deploy(role *iam.Role, artifactBucketLocation *string) error {
 			input.SetVpcConfig(vpcConfig)
 		}
 
+		// Setup visual monitoring baseline, it can be set only on update
+		input.VisualReference, err = c.getVisualReferenceInput(ctx)
+		if err != nil {
+			return err
+		}
+
 		// Update canary
-		_, err = c.clients.synthetics.UpdateCanary(input)
+		_, err = c.clients.Synthetics.UpdateCanaryWithContext(ctx, input)
 	}
 
 	return err
 }
 
-// UpdateTags update canary tags
-func (c *Canary) UpdateTags(region *string, account *string) error {
+func (c *Canary) getCodeInput() (*synthetics.CanaryCodeInput, error) {
+	// Set S3 path for code
+	if len(c.Code.archives3bucket) != 0 && len(c.Code.archives3key) != 0 {
+		return &synthetics.CanaryCodeInput{
+			Handler:  &c.Code.Handler,
+			S3Bucket: &c.Code.archives3bucket,
+			S3Key:    &c.Code.archives3key,
+		}, nil
+	}
+
+	// Load archive path
+	data, err := c.Code.ReadArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	// Set zip file code
+	return &synthetics.CanaryCodeInput{
+		Handler: &c.Code.Handler,
+		ZipFile: data,
+	}, nil
+}
+
+func (c *Canary) getRunConfigInput() *synthetics.CanaryRunConfigInput {
+	return &synthetics.CanaryRunConfigInput{
+		ActiveTracing:        &c.ActiveTracing,
+		EnvironmentVariables: aws.StringMap(c.EnvironmentVariables),
+		MemoryInMB:           &c.MemoryInMB,
+		TimeoutInSeconds:     &c.TimeoutInSeconds,
+	}
+}
+
+func (c *Canary) getVpcConfigInput() *synthetics.VpcConfigInput {
+	if c.HasVpcConfig() == false {
+		return nil
+	}
+	return &synthetics.VpcConfigInput{
+		SecurityGroupIds: aws.StringSlice(c.VpcConfig.SecurityGroupIds),
+		SubnetIds:        aws.StringSlice(c.VpcConfig.SubnetIDs),
+	}
+}
+
+func (c *Canary) getArtifactConfigInput() *synthetics.ArtifactConfigInput_ {
+	if len(c.ArtifactConfig.Encryption.Mode) == 0 {
+		return nil
+	}
+
+	artifactConfig := &synthetics.ArtifactConfigInput_{
+		S3Encryption: &synthetics.S3EncryptionConfig{
+			EncryptionMode: aws.String(c.ArtifactConfig.Encryption.Mode),
+		},
+	}
+	if len(c.ArtifactConfig.Encryption.KmsKey) > 0 {
+		artifactConfig.S3Encryption.KmsKeyArn = aws.String(c.ArtifactConfig.Encryption.KmsKey)
+	}
+	return artifactConfig
+}
+
+// GetDeployTags return configured tags and tags set by the CLI on deployed canary
+func (c *Canary) GetDeployTags() map[string]string {
+	tags := map[string]string{}
+	for key, value := range c.Tags {
+		tags[key] = value
+	}
+	for key, value := range c.GetManagedTags() {
+		tags[key] = value
+	}
+	return tags
+}
+
+// GetManagedTags return tags set by the CLI on canary resources
+func (c *Canary) GetManagedTags() map[string]string {
+	return awsinternal.GetManagedTags(c.Project, c.GetRelativeConfigPath())
+}
+
+// GetRelativeConfigPath return configuration file path relative to configuration root, the git repository
+// top level or the search path, so it does not change with the directory the CLI is executed from
+func (c *Canary) GetRelativeConfigPath() string {
+	if len(c.ConfigPath) == 0 {
+		return ""
+	}
+
+	// Check if path can be made relative
+	absPath, err := resolvePath(c.ConfigPath)
+	if err != nil {
+		return filepath.ToSlash(c.ConfigPath)
+	}
+	if len(c.ConfigRoot) == 0 {
+		return filepath.ToSlash(absPath)
+	}
+	root, err := resolvePath(c.ConfigRoot)
+	if err != nil {
+		return filepath.ToSlash(absPath)
+	}
+	relPath, err := filepath.Rel(root, absPath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return filepath.ToSlash(absPath)
+	}
+
+	return filepath.ToSlash(relPath)
+}
+
+// resolvePath return the absolute path with symbolic links evaluated, as reported by git
+func resolvePath(p string) (string, error) {
+	absPath, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(absPath)
+}
+
+// UpdateTags update canary tags, tags set by the CLI are never removed
+func (c *Canary) UpdateTags(ctx context.Context, region *string, account *string) error {
 	// Build ARN
-	arn := fmt.Sprintf("arn:aws:synthetics:%s:%s:canary:%s", *region, *account, c.Name)
+	arn := c.GetArn(region, account)
+	tags := c.GetDeployTags()
 
 	// Get current tags
-	resTags, err := c.clients.synthetics.ListTagsForResource(&synthetics.ListTagsForResourceInput{
+	resTags, err := c.clients.Synthetics.ListTagsForResourceWithContext(ctx, &synthetics.ListTagsForResourceInput{
 		ResourceArn: &arn,
 	})
 	if err != nil {
 		return err
 	}
 
-	// Skip if not tags are set
-	if len(c.Tags) == 0 && len(resTags.Tags) == 0 {
-		return nil
-	}
-
 	// Check tags to add
 	tagsToAdd := map[string]string{}
-	for key, value := range c.Tags {
+	for key, value := range tags {
 		var foundTagKey *string
 		for currentTagKey, currentTagValue := range resTags.Tags {
 			if key == currentTagKey && value == *currentTagValue {
@@ -257,7 +427,7 @@ func (c *Canary) UpdateTags(region *string, account *string) error {
 
 	// Add missing tags
 	if len(tagsToAdd) > 0 {
-		_, err := c.clients.synthetics.TagResource(&synthetics.TagResourceInput{
+		_, err := c.clients.Synthetics.TagResourceWithContext(ctx, &synthetics.TagResourceInput{
 			ResourceArn: &arn,
 			Tags:        aws.StringMap(tagsToAdd),
 		})
@@ -269,22 +439,17 @@ func (c *Canary) UpdateTags(region *string, account *string) error {
 	// Check accounts ids to remove
 	tagsKeysToRemove := []string{}
 	for currentTagKey := range resTags.Tags {
-		var foundTagKey *string
-		for key := range c.Tags {
-			if key == currentTagKey {
-				foundTagKey = &key
-				break
-			}
+		if strings.HasPrefix(currentTagKey, awsinternal.TagPrefix) {
+			continue
 		}
-
-		if foundTagKey == nil {
+		if _, ok := tags[currentTagKey]; ok == false {
 			tagsKeysToRemove = append(tagsKeysToRemove, currentTagKey)
 		}
 	}
 
 	// Remove unused tags
 	if len(tagsKeysToRemove) > 0 {
-		_, err = c.clients.synthetics.UntagResource(&synthetics.UntagResourceInput{
+		_, err = c.clients.Synthetics.UntagResourceWithContext(ctx, &synthetics.UntagResourceInput{
 			ResourceArn: &arn,
 			TagKeys:     aws.StringSlice(tagsKeysToRemove),
 		})
@@ -296,34 +461,67 @@ func (c *Canary) UpdateTags(region *string, account *string) error {
 	return nil
 }
 
+// UpdateRuntime change only the runtime of deployed canary, code and configuration are not changed
+func (c *Canary) UpdateRuntime(ctx context.Context, runtime string) error {
+	_, err := c.clients.Synthetics.UpdateCanaryWithContext(ctx, &synthetics.UpdateCanaryInput{
+		Name:           &c.Name,
+		RuntimeVersion: &runtime,
+	})
+	return err
+}
+
 // Start canary
-func (c *Canary) Start() error {
-	_, err := c.clients.synthetics.StartCanary(&synthetics.StartCanaryInput{
+func (c *Canary) Start(ctx context.Context) error {
+	_, err := c.clients.Synthetics.StartCanaryWithContext(ctx, &synthetics.StartCanaryInput{
 		Name: &c.Name,
 	})
 	return err
 }
 
 // Stop canary
-func (c *Canary) Stop() error {
-	_, err := c.clients.synthetics.StopCanary(&synthetics.StopCanaryInput{
+func (c *Canary) Stop(ctx context.Context) error {
+	_, err := c.clients.Synthetics.StopCanaryWithContext(ctx, &synthetics.StopCanaryInput{
 		Name: &c.Name,
 	})
 	return err
 }
 
 // GetStatus return canary status
-func (c *Canary) GetStatus() (*synthetics.CanaryStatus, error) {
-	res, err := c.clients.synthetics.GetCanary(&synthetics.GetCanaryInput{
+func (c *Canary) GetStatus(ctx context.Context) (*synthetics.CanaryStatus, error) {
+	res, err := c.clients.Synthetics.GetCanaryWithContext(ctx, &synthetics.GetCanaryInput{
 		Name: &c.Name,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Canary.Status, nil
+}
+
+// WaitStatus poll canary status until check is satisfied or context is done
+func (c *Canary) WaitStatus(ctx context.Context, check func(status *synthetics.CanaryStatus) bool) (*synthetics.CanaryStatus, error) {
+	for {
+		err := aws.SleepWithContext(ctx, c.pollInterval)
+		if err != nil {
+			return nil, err
+		}
+
+		// Get canary status
+		status, err := c.GetStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
 
-	return res.Canary.Status, err
+		// Check canary state
+		if check(status) {
+			return status, nil
+		}
+	}
 }
 
 // GetRuns return canary runs
-func (c *Canary) GetRuns() ([]*synthetics.CanaryRun, error) {
-	res, err := c.clients.synthetics.GetCanaryRuns(&synthetics.GetCanaryRunsInput{
+func (c *Canary) GetRuns(ctx context.Context) ([]*synthetics.CanaryRun, error) {
+	res, err := c.clients.Synthetics.GetCanaryRunsWithContext(ctx, &synthetics.GetCanaryRunsInput{
 		Name: &c.Name,
 	})
 
@@ -331,8 +529,8 @@ func (c *Canary) GetRuns() ([]*synthetics.CanaryRun, error) {
 }
 
 // GetLastRun return the latest canary run
-func (c *Canary) GetLastRun() (*synthetics.CanaryRun, error) {
-	runs, err := c.GetRuns()
+func (c *Canary) GetLastRun(ctx context.Context) (*synthetics.CanaryRun, error) {
+	runs, err := c.GetRuns(ctx)
 	if len(runs) > 0 {
 		return runs[0], nil
 	}
@@ -340,7 +538,7 @@ func (c *Canary) GetLastRun() (*synthetics.CanaryRun, error) {
 }
 
 // GetRunLogs return canary run log
-func (c *Canary) GetRunLogs(run *synthetics.CanaryRun) (*string, error) {
+func (c *Canary) GetRunLogs(ctx context.Context, run *synthetics.CanaryRun) (*string, error) {
 	log := ""
 
 	// Check if not data are set
@@ -348,23 +546,15 @@ func (c *Canary) GetRunLogs(run *synthetics.CanaryRun) (*string, error) {
 		return run.ArtifactS3Location, nil
 	}
 
-	// Elaborate bucket name
-	artifactPath := *run.ArtifactS3Location
-	bucketName := strings.Split(artifactPath, "/")[0]
-
 	// List artifact objects
-	listRes, err := c.clients.s3.ListObjects(&s3.ListObjectsInput{
-		Bucket: &bucketName,
-		Prefix: aws.String(artifactPath[len(bucketName)+1:]),
-	})
-
+	bucketName, objects, err := c.listRunArtifacts(ctx, run)
 	if err != nil {
 		return &log, err
 	}
 
 	// Search for logs
 	logKey := ""
-	for _, object := range listRes.Contents {
+	for _, object := range objects {
 		if path.Ext(*object.Key) == ".txt" {
 			logKey = *object.Key
 			break
@@ -373,30 +563,140 @@ func (c *Canary) GetRunLogs(run *synthetics.CanaryRun) (*string, error) {
 
 	// Check if log was found
 	if len(logKey) == 0 {
-		return &log, fmt.Errorf("Cannot find log txt file in artifact bucket s3://%s", artifactPath)
+		return &log, fmt.Errorf("Cannot find log txt file in artifact bucket s3://%s", *run.ArtifactS3Location)
 	}
 
 	// Retrieve log file content
-	getRes, err := c.clients.s3.GetObject(&s3.GetObjectInput{
+	content, err := c.getArtifact(ctx, bucketName, logKey)
+	if err != nil {
+		return &log, err
+	}
+	log = string(content)
+
+	return &log, nil
+}
+
+// GetRunsPages iterate over canary runs pages, newest first, until fn returns false
+func (c *Canary) GetRunsPages(ctx context.Context, fn func(runs []*synthetics.CanaryRun) bool) error {
+	var nextToken *string
+	for {
+		res, err := c.clients.Synthetics.GetCanaryRunsWithContext(ctx, &synthetics.GetCanaryRunsInput{
+			Name:       &c.Name,
+			NextToken:  nextToken,
+			MaxResults: aws.Int64(100),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Check for next page
+		if fn(res.CanaryRuns) == false || res.NextToken == nil {
+			return nil
+		}
+		nextToken = res.NextToken
+	}
+}
+
+// WaitRun wait until the first run after the provided one is completed, a nil previous run matches
+// the first canary run. Runs are compared with the previous run ID and service start time, since local
+// clock can differ from the service one, so a previous run already deleted by retention is still detected
+func (c *Canary) WaitRun(ctx context.Context, previousRun *synthetics.CanaryRun) (*synthetics.CanaryRun, error) {
+	var previousStarted *time.Time
+	if previousRun != nil && previousRun.Timeline != nil {
+		previousStarted = previousRun.Timeline.Started
+	}
+
+	for {
+		err := aws.SleepWithContext(ctx, 2*c.pollInterval)
+		if err != nil {
+			return nil, err
+		}
+
+		// Search for the oldest run newer than the previous one, runs are listed newest first
+		var found *synthetics.CanaryRun
+		err = c.GetRunsPages(ctx, func(runs []*synthetics.CanaryRun) bool {
+			for _, run := range runs {
+				if previousRun != nil && *run.Id == *previousRun.Id {
+					return false
+				}
+				if previousStarted != nil && run.Timeline != nil && run.Timeline.Started != nil && run.Timeline.Started.After(*previousStarted) == false {
+					return false
+				}
+				found = run
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Check run state
+		if found != nil && *found.Status.State != synthetics.CanaryRunStateRunning {
+			return found, nil
+		}
+	}
+}
+
+// IsManualSchedule check if canary runs only when manually started
+func (c *Canary) IsManualSchedule() bool {
+	return c.Schedule.Expression == "rate(0 hour)" || c.Schedule.Expression == "rate(0 minute)"
+}
+
+// listRunArtifacts return artifact bucket name and objects of a canary run
+func (c *Canary) listRunArtifacts(ctx context.Context, run *synthetics.CanaryRun) (string, []*s3.Object, error) {
+	// Elaborate bucket name
+	artifactPath := strings.TrimPrefix(*run.ArtifactS3Location, "s3://")
+	bucketName := strings.Split(artifactPath, "/")[0]
+
+	// List artifact objects, following pages
+	objects := []*s3.Object{}
+	var marker *string
+	for {
+		listRes, err := c.clients.S3.ListObjectsWithContext(ctx, &s3.ListObjectsInput{
+			Bucket: &bucketName,
+			Prefix: aws.String(artifactPath[len(bucketName)+1:]),
+			Marker: marker,
+		})
+		if err != nil {
+			return bucketName, nil, err
+		}
+		objects = append(objects, listRes.Contents...)
+
+		// Check for next page
+		if aws.BoolValue(listRes.IsTruncated) == false || len(listRes.Contents) == 0 {
+			break
+		}
+		marker = listRes.Contents[len(listRes.Contents)-1].Key
+	}
+
+	return bucketName, objects, nil
+}
+
+// getArtifact return artifact object content
+func (c *Canary) getArtifact(ctx context.Context, bucketName string, key string) ([]byte, error) {
+	getRes, err := c.clients.S3.GetObjectWithContext(ctx, &s3.GetObjectInput{
 		Bucket: &bucketName,
-		Key:    &logKey,
+		Key:    &key,
 	})
 	if err != nil {
-		return &log, err
+		// Objects encrypted with KMS can be read only with decrypt permission on key
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "AccessDenied" && len(c.ArtifactConfig.GetKmsKey()) > 0 {
+			return nil, fmt.Errorf("Access denied reading artifact %s, check kms:Decrypt permission on key %s: %s", key, c.ArtifactConfig.GetKmsKey(), err)
+		}
+		return nil, err
 	}
+	defer getRes.Body.Close()
 
 	// Read object content
 	buf := new(bytes.Buffer)
-	buf.ReadFrom(getRes.Body)
-	log = buf.String()
-
-	return &log, nil
+	_, err = buf.ReadFrom(getRes.Body)
+	return buf.Bytes(), err
 }
 
 // Remove canary
-func (c *Canary) Remove() error {
+func (c *Canary) Remove(ctx context.Context) error {
 	// Get canary
-	canaryGet, err := c.clients.synthetics.GetCanary(&synthetics.GetCanaryInput{
+	canaryGet, err := c.clients.Synthetics.GetCanaryWithContext(ctx, &synthetics.GetCanaryInput{
 		Name: &c.Name,
 	})
 	if err != nil {
@@ -404,7 +704,7 @@ func (c *Canary) Remove() error {
 	}
 
 	// Delete canary
-	_, err = c.clients.synthetics.DeleteCanary(&synthetics.DeleteCanaryInput{
+	_, err = c.clients.Synthetics.DeleteCanaryWithContext(ctx, &synthetics.DeleteCanaryInput{
 		Name: &c.Name,
 	})
 	if err != nil {
@@ -413,13 +713,13 @@ func (c *Canary) Remove() error {
 
 	// Delete related layer (ignore error if not exist)
 	layerName := fmt.Sprintf("cwsyn-%s-%s", c.Name, *canaryGet.Canary.Id)
-	layerList, _ := c.clients.lambda.ListLayerVersions(&lambda.ListLayerVersionsInput{
+	layerList, _ := c.clients.Lambda.ListLayerVersionsWithContext(ctx, &lambda.ListLayerVersionsInput{
 		LayerName: &layerName,
 	})
 
 	// Delete all layer's versions
 	for _, version := range layerList.LayerVersions {
-		_, err = c.clients.lambda.DeleteLayerVersion(&lambda.DeleteLayerVersionInput{
+		_, err = c.clients.Lambda.DeleteLayerVersionWithContext(ctx, &lambda.DeleteLayerVersionInput{
 			LayerName:     &layerName,
 			VersionNumber: version.Version,
 		})
@@ -429,7 +729,7 @@ func (c *Canary) Remove() error {
 	}
 
 	// Delete related function (ignore error if not exist)
-	c.clients.lambda.DeleteFunction(&lambda.DeleteFunctionInput{
+	c.clients.Lambda.DeleteFunctionWithContext(ctx, &lambda.DeleteFunctionInput{
 		FunctionName: &layerName,
 	})
 
@@ -446,6 +746,16 @@ func (c *Canary) IsPythonRuntime() bool {
 	return strings.Contains(c.RuntimeVersion, "python")
 }
 
+// GetCodePathPrefix return the archive path prefix required by canary runtime
+func (c *Canary) GetCodePathPrefix() string {
+	if c.IsPythonRuntime() {
+		return "python"
+	} else if c.IsNodeRuntime() {
+		return "nodejs/node_modules"
+	}
+	return ""
+}
+
 // HasVpcConfig check if is VPC access is configured
 func (c *Canary) HasVpcConfig() bool {
 	return len(c.VpcConfig.SubnetIDs) > 0
@@ -3,6 +3,9 @@ package canary
 import (
 	"archive/zip"
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -13,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
 	"github.com/daaru00/aws-canary-cli/internal/bucket"
 )
 
@@ -22,15 +26,15 @@ type Code struct {
 	archivepath     string
 	archives3bucket string
 	archives3key    string
-	clients         *clients
+	clients         *awsinternal.Clients
 
 	Src     string   `yaml:"src" json:"src"`
 	Handler string   `yaml:"handler" json:"handler"`
 	Exclude []string `yaml:"exclude" json:"exclude"`
 }
 
-// CreateArchive create a ZIP archive from code path
-func (c *Code) CreateArchive(name *string, pathprefix *string) error {
+// CreateArchive create a ZIP archive from code path, archive is deleted if creation fails
+func (c *Code) CreateArchive(ctx context.Context, name *string, pathprefix *string) error {
 	c.archivename = fmt.Sprintf("%s.zip", *name)
 	c.archivepath = path.Join(os.TempDir(), c.archivename)
 
@@ -48,6 +52,12 @@ func (c *Code) CreateArchive(name *string, pathprefix *string) error {
 		if err != nil {
 			return err
 		}
+
+		// Stop if operation was interrupted
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
 		// Skip directories
 		if info.IsDir() {
 			return nil
@@ -80,6 +90,7 @@ func (c *Code) CreateArchive(name *string, pathprefix *string) error {
 		if err != nil {
 			return err
 		}
+		defer fsFile.Close()
 		_, err = io.Copy(zipFile, fsFile)
 		if err != nil {
 			return err
@@ -87,12 +98,16 @@ func (c *Code) CreateArchive(name *string, pathprefix *string) error {
 		return nil
 	})
 	if err != nil {
+		destinationFile.Close()
+		c.DeleteArchive()
 		return err
 	}
 
 	// Close ZIP archive
 	err = codeZip.Close()
+	destinationFile.Close()
 	if err != nil {
+		c.DeleteArchive()
 		return err
 	}
 
@@ -110,7 +125,7 @@ func (c *Code) DeleteArchive() error {
 }
 
 // Upload will upload archive to S3
-func (c *Code) Upload(bucket *bucket.Bucket, prefix *string) error {
+func (c *Code) Upload(ctx context.Context, bucket *bucket.Bucket, key *string) error {
 	// Open archive file
 	file, err := os.Open(c.archivepath)
 	if err != nil {
@@ -119,11 +134,10 @@ func (c *Code) Upload(bucket *bucket.Bucket, prefix *string) error {
 	defer file.Close()
 
 	// Set archive s3 location
-	c.archives3bucket = *bucket.Name
-	c.archives3key = path.Join(*prefix, c.archivename)
+	c.SetS3Location(*bucket.Name, *key)
 
 	// Upload archive
-	_, err = c.clients.s3uploader.Upload(&s3manager.UploadInput{
+	_, err = c.clients.S3Uploader.UploadWithContext(ctx, &s3manager.UploadInput{
 		Bucket: &c.archives3bucket,
 		Key:    &c.archives3key,
 		Body:   file,
@@ -132,8 +146,44 @@ func (c *Code) Upload(bucket *bucket.Bucket, prefix *string) error {
 	return err
 }
 
+// SetS3Location set the S3 location of an already uploaded archive
+func (c *Code) SetS3Location(bucket string, key string) {
+	c.archives3bucket = bucket
+	c.archives3key = key
+}
+
+// GetArchiveHash return the SHA256 hash of archive content
+func (c *Code) GetArchiveHash() (string, error) {
+	file, err := os.Open(c.archivepath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetGitCommit return the git commit of code path, empty when not in a git repository
+func (c *Code) GetGitCommit(ctx context.Context) string {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = c.Src
+
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
 // InstallNpmDependencies will install npm dependencies
-func (c *Code) InstallNpmDependencies() (string, error) {
+func (c *Code) InstallNpmDependencies(ctx context.Context) (string, error) {
 	var outBuffer, errBuffer bytes.Buffer
 
 	// Check if package.json exist
@@ -142,7 +192,7 @@ func (c *Code) InstallNpmDependencies() (string, error) {
 	}
 
 	// Prepare npm dependencies install command
-	cmd := exec.Command("npm", "install", "--production")
+	cmd := exec.CommandContext(ctx, "npm", "install", "--production")
 	cmd.Dir = c.Src
 
 	// Set outputs
@@ -159,7 +209,7 @@ func (c *Code) InstallNpmDependencies() (string, error) {
 }
 
 // InstallPipDependencies will install pip dependencies
-func (c *Code) InstallPipDependencies() (string, error) {
+func (c *Code) InstallPipDependencies(ctx context.Context) (string, error) {
 	var outBuffer, errBuffer bytes.Buffer
 
 	// Check if requirements.txt exist
@@ -168,7 +218,7 @@ func (c *Code) InstallPipDependencies() (string, error) {
 	}
 
 	// Prepare npm dependencies install command
-	cmd := exec.Command("pip", "install", "-r", "requirements.txt")
+	cmd := exec.CommandContext(ctx, "pip", "install", "-r", "requirements.txt")
 	cmd.Dir = c.Src
 
 	// Set outputs
@@ -0,0 +1,147 @@
+package canary
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+)
+
+// Difference structure, a configuration value that differs from the deployed one
+type Difference struct {
+	Field    string
+	Expected string
+	Actual   string
+}
+
+// GetDrift compare canary configuration, tags and code with the deployed canary,
+// environment variables are not compared because they are not returned by Synthetics
+func (c *Canary) GetDrift(ctx context.Context, region *string, account *string, roleArn string) ([]*Difference, error) {
+	differences := []*Difference{}
+	add := func(field string, expected interface{}, actual interface{}) {
+		expectedValue := fmt.Sprint(expected)
+		actualValue := fmt.Sprint(actual)
+		if expectedValue != actualValue {
+			differences = append(differences, &Difference{
+				Field:    field,
+				Expected: expectedValue,
+				Actual:   actualValue,
+			})
+		}
+	}
+
+	res, err := c.clients.Synthetics.GetCanaryWithContext(ctx, &synthetics.GetCanaryInput{
+		Name: &c.Name,
+	})
+	if err != nil {
+		return differences, err
+	}
+	deployed := res.Canary
+
+	// Compare configuration
+	add("runtime", c.RuntimeVersion, aws.StringValue(deployed.RuntimeVersion))
+	add("role", roleArn, aws.StringValue(deployed.ExecutionRoleArn))
+	add("retention.failure", c.Retention.FailureRetentionPeriod, aws.Int64Value(deployed.FailureRetentionPeriodInDays))
+	add("retention.success", c.Retention.SuccessRetentionPeriod, aws.Int64Value(deployed.SuccessRetentionPeriodInDays))
+	if deployed.Code != nil {
+		add("code.handler", c.Code.Handler, aws.StringValue(deployed.Code.Handler))
+	}
+	if deployed.Schedule != nil {
+		add("schedule.expression", c.Schedule.Expression, aws.StringValue(deployed.Schedule.Expression))
+		add("schedule.duration", c.Schedule.DurationInSeconds, aws.Int64Value(deployed.Schedule.DurationInSeconds))
+	}
+	if deployed.RunConfig != nil {
+		add("memory", c.MemoryInMB, aws.Int64Value(deployed.RunConfig.MemoryInMB))
+		add("timeout", c.TimeoutInSeconds, aws.Int64Value(deployed.RunConfig.TimeoutInSeconds))
+		add("tracing", c.ActiveTracing, aws.BoolValue(deployed.RunConfig.ActiveTracing))
+	}
+	deployedVpc := &synthetics.VpcConfigOutput{}
+	if deployed.VpcConfig != nil {
+		deployedVpc = deployed.VpcConfig
+	}
+	add("vpc.subnets", sortedList(c.VpcConfig.SubnetIDs), sortedList(aws.StringValueSlice(deployedVpc.SubnetIds)))
+	add("vpc.securityGroups", sortedList(c.VpcConfig.SecurityGroupIds), sortedList(aws.StringValueSlice(deployedVpc.SecurityGroupIds)))
+	if len(c.ArtifactConfig.Encryption.Mode) > 0 {
+		deployedEncryption := &synthetics.S3EncryptionConfig{}
+		if deployed.ArtifactConfig != nil && deployed.ArtifactConfig.S3Encryption != nil {
+			deployedEncryption = deployed.ArtifactConfig.S3Encryption
+		}
+		add("artifacts.encryption.mode", c.ArtifactConfig.Encryption.Mode, aws.StringValue(deployedEncryption.EncryptionMode))
+		add("artifacts.encryption.kmsKey", c.ArtifactConfig.Encryption.KmsKey, aws.StringValue(deployedEncryption.KmsKeyArn))
+	}
+
+	// Compare tags with the ones set by deploy, AWS reserved tags and ownership tags no longer set are ignored
+	arn := c.GetArn(region, account)
+	resTags, err := c.clients.Synthetics.ListTagsForResourceWithContext(ctx, &synthetics.ListTagsForResourceInput{
+		ResourceArn: &arn,
+	})
+	if err != nil {
+		return differences, err
+	}
+	tags := c.GetDeployTags()
+	deployedTags := aws.StringValueMap(resTags.Tags)
+	for key, value := range tags {
+		add("tags."+key, value, deployedTags[key])
+	}
+	for key, value := range deployedTags {
+		if _, ok := tags[key]; ok || strings.HasPrefix(key, "aws:") || strings.HasPrefix(key, awsinternal.TagPrefix) {
+			continue
+		}
+		add("tags."+key, "", value)
+	}
+
+	// Compare code hash with the code layer deployed by Synthetics
+	if deployed.Code != nil && deployed.Code.SourceLocationArn != nil {
+		codeHash, err := c.getCodeSha256(ctx)
+		if err != nil {
+			return differences, err
+		}
+
+		layer, err := c.clients.Lambda.GetLayerVersionByArnWithContext(ctx, &lambda.GetLayerVersionByArnInput{
+			Arn: deployed.Code.SourceLocationArn,
+		})
+		if err != nil {
+			return differences, err
+		}
+		add("code", codeHash, aws.StringValue(layer.Content.CodeSha256))
+	}
+
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].Field < differences[j].Field
+	})
+	return differences, nil
+}
+
+// getCodeSha256 create a temporary code archive and return its base64 encoded SHA256 hash
+func (c *Canary) getCodeSha256(ctx context.Context) (string, error) {
+	pathPrefix := c.GetCodePathPrefix()
+	err := c.Code.CreateArchive(ctx, &c.Name, &pathPrefix)
+	if err != nil {
+		return "", err
+	}
+	defer c.Code.DeleteArchive()
+
+	hash, err := c.Code.GetArchiveHash()
+	if err != nil {
+		return "", err
+	}
+	sum, err := hex.DecodeString(hash)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(sum), nil
+}
+
+func sortedList(values []string) string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
@@ -0,0 +1,89 @@
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+)
+
+// DryRun test canary code and configuration with a dry run of the deployed canary and wait for its result,
+// deployed canary is not changed. The execution role of the deployed canary is used, so a new role or policy
+// is not applied before the dry run passes
+func (c *Canary) DryRun(ctx context.Context, artifactBucketLocation *string) (*synthetics.CanaryRun, error) {
+	codeInputConfig, err := c.getCodeInput()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.startDryRun(ctx, &awsinternal.StartCanaryDryRunInput{
+		Name:                         &c.Name,
+		ArtifactS3Location:           artifactBucketLocation,
+		ArtifactConfig:               c.getArtifactConfigInput(),
+		Code:                         codeInputConfig,
+		FailureRetentionPeriodInDays: &c.Retention.FailureRetentionPeriod,
+		SuccessRetentionPeriodInDays: &c.Retention.SuccessRetentionPeriod,
+		RunConfig:                    c.getRunConfigInput(),
+		RuntimeVersion:               &c.RuntimeVersion,
+		VpcConfig:                    c.getVpcConfigInput(),
+	})
+}
+
+// DryRunRuntime test a runtime with a dry run of the deployed canary and wait for its result,
+// code and configuration of the deployed canary are used
+func (c *Canary) DryRunRuntime(ctx context.Context, runtime string) (*synthetics.CanaryRun, error) {
+	return c.startDryRun(ctx, &awsinternal.StartCanaryDryRunInput{
+		Name:           &c.Name,
+		RuntimeVersion: &runtime,
+	})
+}
+
+// GetDryRunFailure return the failure reason of a dry run followed by its logs, when available
+func (c *Canary) GetDryRunFailure(ctx context.Context, run *synthetics.CanaryRun) string {
+	reason := aws.StringValue(run.Status.StateReason)
+	if len(reason) == 0 {
+		reason = aws.StringValue(run.Status.State)
+	}
+
+	// Attach dry run logs, saved in artifact bucket
+	if run.ArtifactS3Location == nil {
+		return reason
+	}
+	logs, err := c.GetRunLogs(ctx, run)
+	if err != nil {
+		c.Logf("Warning: cannot get dry run logs: %s", err)
+		return reason
+	}
+
+	return fmt.Sprintf("%s\n%s", reason, *logs)
+}
+
+func (c *Canary) startDryRun(ctx context.Context, input *awsinternal.StartCanaryDryRunInput) (*synthetics.CanaryRun, error) {
+	// Start dry run
+	res, err := c.clients.SyntheticsDryRun.StartCanaryDryRunWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	dryRunID := res.DryRunConfig.DryRunId
+
+	// Wait until dry run run is completed
+	for {
+		runsRes, err := c.clients.SyntheticsDryRun.GetCanaryDryRunRunsWithContext(ctx, &awsinternal.GetCanaryDryRunRunsInput{
+			Name:     &c.Name,
+			DryRunId: dryRunID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(runsRes.CanaryRuns) > 0 && *runsRes.CanaryRuns[0].Status.State != synthetics.CanaryRunStateRunning {
+			return runsRes.CanaryRuns[0], nil
+		}
+
+		err = aws.SleepWithContext(ctx, 2*c.pollInterval)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package canary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/internal/bucket"
+)
+
+const (
+	// VersionActionDeploy is the history action of a deployed version
+	VersionActionDeploy = "deploy"
+	// VersionActionRollback is the history action of a rolled back version
+	VersionActionRollback = "rollback"
+)
+
+// Version structure, a deployed code archive and configuration
+type Version struct {
+	ID      string          `json:"id"`
+	Hash    string          `json:"hash"`
+	Commit  string          `json:"commit"`
+	Archive string          `json:"archive"`
+	Action  string          `json:"action"`
+	User    string          `json:"user"`
+	Date    time.Time       `json:"date"`
+	Config  json.RawMessage `json:"config"`
+}
+
+// NewVersion create a new version from code archive and current configuration,
+// version id is composed by git commit (when available) and archive content hash
+func (c *Canary) NewVersion(ctx context.Context, prefix *string) (*Version, error) {
+	hash, err := c.Code.GetArchiveHash()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := c.getConfigSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	version := &Version{
+		ID:     hash[:12],
+		Hash:   hash,
+		Commit: c.Code.GetGitCommit(ctx),
+		Action: VersionActionDeploy,
+		Config: config,
+	}
+	if len(version.Commit) > 0 {
+		version.ID = fmt.Sprintf("%s-%s", version.Commit[:7], version.ID)
+	}
+	version.Archive = path.Join(*prefix, c.Name, version.ID+".zip")
+
+	return version, nil
+}
+
+// getConfigSnapshot return canary configuration without environment variables,
+// their values can contain secrets and must not be stored in source bucket
+func (c *Canary) getConfigSnapshot() (json.RawMessage, error) {
+	content, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	err = json.Unmarshal(content, &fields)
+	if err != nil {
+		return nil, err
+	}
+	delete(fields, "env")
+
+	return json.Marshal(fields)
+}
+
+// LoadConfig load version configuration snapshot into canary,
+// environment variables are not part of the snapshot and keep the current values
+func (v *Version) LoadConfig(c *Canary) error {
+	return json.Unmarshal(v.Config, c)
+}
+
+// GetDeployedEnvironmentVariables return environment variables of deployed canary function,
+// used to restore them when a configuration snapshot is loaded
+func (c *Canary) GetDeployedEnvironmentVariables(ctx context.Context) (map[string]string, error) {
+	res, err := c.clients.Synthetics.GetCanaryWithContext(ctx, &synthetics.GetCanaryInput{
+		Name: &c.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Canary.EngineArn == nil {
+		return nil, nil
+	}
+
+	function, err := c.clients.Lambda.GetFunctionConfigurationWithContext(ctx, &lambda.GetFunctionConfigurationInput{
+		FunctionName: res.Canary.EngineArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if function.Environment == nil || len(function.Environment.Variables) == 0 {
+		return nil, nil
+	}
+
+	return aws.StringValueMap(function.Environment.Variables), nil
+}
+
+// GetShortCommit return abbreviated git commit, "-" when not available
+func (v *Version) GetShortCommit() string {
+	if len(v.Commit) < 7 {
+		return "-"
+	}
+	return v.Commit[:7]
+}
+
+// GetHistory return deployed versions stored in source bucket, oldest first
+func (c *Canary) GetHistory(ctx context.Context, sourceBucket *bucket.Bucket, prefix *string) ([]*Version, error) {
+	history := []*Version{}
+
+	// Load versions objects
+	var continuationToken *string
+	for {
+		res, err := c.clients.S3.ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+			Bucket:            sourceBucket.Name,
+			Prefix:            aws.String(c.getHistoryPrefix(prefix)),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return history, err
+		}
+
+		for _, object := range res.Contents {
+			content, err := c.getObject(ctx, sourceBucket, object.Key)
+			if err != nil {
+				return history, err
+			}
+
+			version := &Version{}
+			err = json.Unmarshal(content, version)
+			if err != nil {
+				return history, err
+			}
+			history = append(history, version)
+		}
+
+		if aws.BoolValue(res.IsTruncated) == false {
+			break
+		}
+		continuationToken = res.NextContinuationToken
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Date.Before(history[j].Date)
+	})
+	return history, nil
+}
+
+// AddHistory store a version in source bucket, each version is a separate object
+// so concurrent deploys cannot overwrite each other records
+func (c *Canary) AddHistory(ctx context.Context, sourceBucket *bucket.Bucket, prefix *string, version *Version) error {
+	content, err := json.MarshalIndent(version, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	key := path.Join(c.getHistoryPrefix(prefix), fmt.Sprintf("%s-%s.json", version.Date.UTC().Format("20060102T150405.000000000Z"), version.ID))
+	_, err = c.clients.S3Uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: sourceBucket.Name,
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(content),
+	})
+	return err
+}
+
+func (c *Canary) getObject(ctx context.Context, sourceBucket *bucket.Bucket, key *string) ([]byte, error) {
+	res, err := c.clients.S3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: sourceBucket.Name,
+		Key:    key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return ioutil.ReadAll(res.Body)
+}
+
+func (c *Canary) getHistoryPrefix(prefix *string) string {
+	return path.Join(*prefix, c.Name, "history") + "/"
+}
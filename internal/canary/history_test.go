@@ -0,0 +1,65 @@
+package canary
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daaru00/aws-canary-cli/internal/bucket"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestHistory(t *testing.T) {
+	backend := fake.NewBackend()
+	backend.CreateBuckets()
+	clients := fake.NewClients(backend)
+	bucketName := "cw-syn-sources-" + backend.AccountID + "-" + backend.Region
+	sourceBucket := bucket.New(clients, &bucketName)
+	prefix := backend.Region
+	ctx := context.Background()
+
+	c := New(clients, "test")
+	date := time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC)
+	for _, id := range []string{"second", "first"} {
+		err := c.AddHistory(ctx, sourceBucket, &prefix, &Version{ID: id, Date: date})
+		if err != nil {
+			t.Fatal(err)
+		}
+		date = date.Add(-time.Hour)
+	}
+
+	history, err := c.GetHistory(ctx, sourceBucket, &prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, version := range history {
+		ids = append(ids, version.ID)
+	}
+	if strings.Join(ids, ",") != "first,second" {
+		t.Errorf("expected versions first,second, found %s", strings.Join(ids, ","))
+	}
+}
+
+func TestConfigSnapshotExcludeEnvironmentVariables(t *testing.T) {
+	c := New(fake.NewClients(fake.NewBackend()), "test")
+	c.EnvironmentVariables = map[string]string{"API_KEY": "secret"}
+
+	snapshot, err := c.getConfigSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(snapshot), "secret") {
+		t.Errorf("snapshot contains environment variables values: %s", snapshot)
+	}
+
+	// Loading snapshot must keep current environment variables
+	err = (&Version{Config: snapshot}).LoadConfig(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.EnvironmentVariables["API_KEY"] != "secret" {
+		t.Errorf("expected environment variables to be kept, found %v", c.EnvironmentVariables)
+	}
+}
@@ -0,0 +1,92 @@
+package canary
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+)
+
+// GetLogGroupName return the log group name of canary Lambda function
+func (c *Canary) GetLogGroupName(ctx context.Context) (string, error) {
+	canaryGet, err := c.clients.Synthetics.GetCanaryWithContext(ctx, &synthetics.GetCanaryInput{
+		Name: &c.Name,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/aws/lambda/cwsyn-%s-%s", c.Name, *canaryGet.Canary.Id), nil
+}
+
+// FollowLogs poll canary log group for new events, matching the optional filter pattern,
+// and call fn for each of them until the context is done
+func (c *Canary) FollowLogs(ctx context.Context, since time.Time, filterPattern string, fn func(event *cloudwatchlogs.FilteredLogEvent)) error {
+	logGroupName, err := c.GetLogGroupName(ctx)
+	if err != nil {
+		return err
+	}
+
+	startTime := since.UnixNano() / int64(time.Millisecond)
+	seen := map[string]int64{}
+	for {
+		lastTime := startTime
+		var nextToken *string
+
+		// Retrieve all new events pages
+		for {
+			input := &cloudwatchlogs.FilterLogEventsInput{
+				LogGroupName: &logGroupName,
+				StartTime:    aws.Int64(startTime),
+				NextToken:    nextToken,
+			}
+			if len(filterPattern) > 0 {
+				input.FilterPattern = aws.String(filterPattern)
+			}
+			res, err := c.clients.CloudWatchLogs.FilterLogEventsWithContext(ctx, input)
+
+			// Log group is created by the first run
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == cloudwatchlogs.ErrCodeResourceNotFoundException {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			// Events with the same timestamp of the previous poll are returned again
+			for _, event := range res.Events {
+				if _, ok := seen[*event.EventId]; ok {
+					continue
+				}
+				seen[*event.EventId] = *event.Timestamp
+				fn(event)
+
+				if *event.Timestamp > lastTime {
+					lastTime = *event.Timestamp
+				}
+			}
+
+			if res.NextToken == nil {
+				break
+			}
+			nextToken = res.NextToken
+		}
+
+		// Forget events older than the next poll start time
+		startTime = lastTime
+		for id, timestamp := range seen {
+			if timestamp < startTime {
+				delete(seen, id)
+			}
+		}
+
+		err = aws.SleepWithContext(ctx, 2*c.pollInterval)
+		if err != nil {
+			return err
+		}
+	}
+}
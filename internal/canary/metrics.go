@@ -0,0 +1,179 @@
+package canary
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+)
+
+// Canary metrics and the statistic used to aggregate them
+var canaryMetrics = []struct {
+	Name string
+	Stat string
+}{
+	{"SuccessPercent", cloudwatch.StatisticAverage},
+	{"Duration", cloudwatch.StatisticAverage},
+	{"Failed", cloudwatch.StatisticSum},
+}
+
+// Step metrics and the statistic used to aggregate them
+var stepMetrics = []struct {
+	Name string
+	Stat string
+}{
+	{"SuccessPercent", cloudwatch.StatisticAverage},
+	{"Duration", cloudwatch.StatisticAverage},
+}
+
+// Metric structure, the values of a canary metric in a time range
+type Metric struct {
+	Name       string      `json:"name"`
+	Step       string      `json:"step,omitempty"`
+	Stat       string      `json:"stat"`
+	Timestamps []time.Time `json:"timestamps"`
+	Values     []float64   `json:"values"`
+}
+
+// Aggregate return the metric value over the whole time range, using the metric statistic
+func (m *Metric) Aggregate() (float64, bool) {
+	if len(m.Values) == 0 {
+		return 0, false
+	}
+
+	total := 0.0
+	for _, value := range m.Values {
+		total += value
+	}
+	if m.Stat == cloudwatch.StatisticSum {
+		return total, true
+	}
+	return total / float64(len(m.Values)), true
+}
+
+// GetStepNames return the names of steps that published metrics
+func (c *Canary) GetStepNames(ctx context.Context) ([]string, error) {
+	names := []string{}
+	found := map[string]bool{}
+
+	var nextToken *string
+	for {
+		res, err := c.clients.CloudWatch.ListMetricsWithContext(ctx, &cloudwatch.ListMetricsInput{
+			Namespace:  aws.String(awsinternal.MetricsNamespace),
+			MetricName: aws.String("SuccessPercent"),
+			Dimensions: []*cloudwatch.DimensionFilter{
+				{
+					Name:  aws.String("CanaryName"),
+					Value: &c.Name,
+				},
+				{
+					Name: aws.String("StepName"),
+				},
+			},
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return names, err
+		}
+
+		// Collect step names
+		for _, metric := range res.Metrics {
+			for _, dimension := range metric.Dimensions {
+				if *dimension.Name == "StepName" && found[*dimension.Value] == false {
+					found[*dimension.Value] = true
+					names = append(names, *dimension.Value)
+				}
+			}
+		}
+
+		if res.NextToken == nil {
+			return names, nil
+		}
+		nextToken = res.NextToken
+	}
+}
+
+// GetMetrics return canary metrics, and optionally steps metrics, in the provided time range
+func (c *Canary) GetMetrics(ctx context.Context, since time.Time, until time.Time, period time.Duration, steps bool) ([]*Metric, error) {
+	metrics := []*Metric{}
+	queries := []*cloudwatch.MetricDataQuery{}
+
+	addQuery := func(name string, stat string, dimensions []*cloudwatch.Dimension, step string) {
+		metrics = append(metrics, &Metric{
+			Name:       name,
+			Step:       step,
+			Stat:       stat,
+			Timestamps: []time.Time{},
+			Values:     []float64{},
+		})
+		queries = append(queries, &cloudwatch.MetricDataQuery{
+			Id: aws.String(fmt.Sprintf("m%d", len(queries))),
+			MetricStat: &cloudwatch.MetricStat{
+				Metric: &cloudwatch.Metric{
+					Namespace:  aws.String(awsinternal.MetricsNamespace),
+					MetricName: aws.String(name),
+					Dimensions: dimensions,
+				},
+				Period: aws.Int64(int64(period.Seconds())),
+				Stat:   aws.String(stat),
+			},
+		})
+	}
+
+	// Build canary queries
+	canaryDimension := &cloudwatch.Dimension{
+		Name:  aws.String("CanaryName"),
+		Value: &c.Name,
+	}
+	for _, metric := range canaryMetrics {
+		addQuery(metric.Name, metric.Stat, []*cloudwatch.Dimension{canaryDimension}, "")
+	}
+
+	// Build steps queries
+	if steps {
+		stepNames, err := c.GetStepNames(ctx)
+		if err != nil {
+			return metrics, err
+		}
+		for _, stepName := range stepNames {
+			stepDimension := &cloudwatch.Dimension{
+				Name:  aws.String("StepName"),
+				Value: aws.String(stepName),
+			}
+			for _, metric := range stepMetrics {
+				addQuery(metric.Name, metric.Stat, []*cloudwatch.Dimension{canaryDimension, stepDimension}, stepName)
+			}
+		}
+	}
+
+	// Retrieve metrics data, following pages
+	var nextToken *string
+	for {
+		res, err := c.clients.CloudWatch.GetMetricDataWithContext(ctx, &cloudwatch.GetMetricDataInput{
+			StartTime:         aws.Time(since),
+			EndTime:           aws.Time(until),
+			MetricDataQueries: queries,
+			ScanBy:            aws.String(cloudwatch.ScanByTimestampAscending),
+			NextToken:         nextToken,
+		})
+		if err != nil {
+			return metrics, err
+		}
+
+		// Collect values by query id
+		for _, result := range res.MetricDataResults {
+			var index int
+			fmt.Sscanf(*result.Id, "m%d", &index)
+			metrics[index].Timestamps = append(metrics[index].Timestamps, aws.TimeValueSlice(result.Timestamps)...)
+			metrics[index].Values = append(metrics[index].Values, aws.Float64ValueSlice(result.Values)...)
+		}
+
+		if res.NextToken == nil {
+			return metrics, nil
+		}
+		nextToken = res.NextToken
+	}
+}
@@ -0,0 +1,150 @@
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/synthetics"
+)
+
+// Report structure, the SyntheticsReport JSON file written by canary runtime
+type Report struct {
+	CanaryName     string         `json:"canaryName"`
+	RunID          string         `json:"canaryRunId"`
+	Status         string         `json:"status"`
+	FailureReason  string         `json:"failureReason,omitempty"`
+	StartTime      time.Time      `json:"startTime"`
+	EndTime        time.Time      `json:"endTime"`
+	CustomerScript CustomerScript `json:"customerScript"`
+}
+
+// CustomerScript structure, the canary script execution with its steps
+type CustomerScript struct {
+	Status    string    `json:"status"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Steps     []*Step   `json:"steps"`
+}
+
+// GetSteps return steps executed by canary script
+func (r *Report) GetSteps() []*Step {
+	return r.CustomerScript.Steps
+}
+
+// Step structure
+type Step struct {
+	Name          string        `json:"stepName"`
+	Status        string        `json:"status"`
+	FailureReason string        `json:"failureReason,omitempty"`
+	StartTime     time.Time     `json:"startTime"`
+	EndTime       time.Time     `json:"endTime"`
+	Screenshots   []*Screenshot `json:"screenshots,omitempty"`
+	Request       *HTTPRequest  `json:"request,omitempty"`
+	Response      *HTTPResponse `json:"response,omitempty"`
+}
+
+// Screenshot structure
+type Screenshot struct {
+	FileName            string                 `json:"fileName"`
+	PageURL             string                 `json:"pageUrl,omitempty"`
+	VisualCompareResult map[string]interface{} `json:"visualCompareResult,omitempty"`
+}
+
+// IsVisualComparisonFailed check if screenshot differs from the visual monitoring baseline
+func (s *Screenshot) IsVisualComparisonFailed() bool {
+	result, ok := s.VisualCompareResult["result"]
+	return ok && strings.EqualFold(fmt.Sprint(result), "FAILED")
+}
+
+// GetVisualVariance return the variance from the visual monitoring baseline, empty when not available
+func (s *Screenshot) GetVisualVariance() string {
+	variance, ok := s.VisualCompareResult["variance"]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(variance)
+}
+
+// HTTPRequest structure, the request executed by an API canary step
+type HTTPRequest struct {
+	Method  string                 `json:"method"`
+	URL     string                 `json:"url"`
+	Headers map[string]interface{} `json:"headers,omitempty"`
+}
+
+// String return request summary
+func (r *HTTPRequest) String() string {
+	return fmt.Sprintf("%s %s", r.Method, r.URL)
+}
+
+// HTTPResponse structure, the response received by an API canary step
+type HTTPResponse struct {
+	StatusCode int                    `json:"statusCode"`
+	StatusText string                 `json:"statusText,omitempty"`
+	Headers    map[string]interface{} `json:"headers,omitempty"`
+}
+
+// String return response summary
+func (r *HTTPResponse) String() string {
+	return strings.TrimSpace(fmt.Sprintf("%d %s", r.StatusCode, r.StatusText))
+}
+
+// IsPassed check if step succeeded, runtimes report it as "succeeded"
+func (s *Step) IsPassed() bool {
+	return strings.EqualFold(s.Status, "succeeded") || strings.EqualFold(s.Status, "PASSED")
+}
+
+// Duration return step execution time
+func (s *Step) Duration() time.Duration {
+	if s.StartTime.IsZero() || s.EndTime.IsZero() {
+		return 0
+	}
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// GetRunReport return canary run report
+func (c *Canary) GetRunReport(ctx context.Context, run *synthetics.CanaryRun) (*Report, error) {
+	// Check if not data are set
+	if *run.ArtifactS3Location == "No data" {
+		return nil, fmt.Errorf("No artifacts found for run %s", *run.Id)
+	}
+
+	// List artifact objects
+	bucketName, objects, err := c.listRunArtifacts(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+
+	// Search for report
+	reportKey := ""
+	for _, object := range objects {
+		if strings.HasPrefix(path.Base(*object.Key), "SyntheticsReport") && path.Ext(*object.Key) == ".json" {
+			reportKey = *object.Key
+			break
+		}
+	}
+
+	// Check if report was found
+	if len(reportKey) == 0 {
+		return nil, fmt.Errorf("Cannot find report json file in artifact bucket s3://%s", *run.ArtifactS3Location)
+	}
+
+	// Retrieve report file content
+	content, err := c.getArtifact(ctx, bucketName, reportKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse report
+	report := &Report{}
+	err = json.Unmarshal(content, report)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot parse report %s: %s", reportKey, err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,95 @@
+package canary
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// reportSample is a SyntheticsReport written by syn-nodejs-puppeteer runtime, trimmed to relevant fields
+const reportSample = `{
+  "canaryName": "test-js-web",
+  "canaryRunId": "2f6b7a62-0f0d-4a0a-9d3c-3f1e4c5b6a7d",
+  "artifactLocation": "cw-syn-results-123456789012-us-east-1/canary/us-east-1/test-js-web",
+  "status": "FAILED",
+  "startTime": "2024-05-10T08:00:01.123Z",
+  "endTime": "2024-05-10T08:00:12.456Z",
+  "customerScript": {
+    "status": "FAILED",
+    "startTime": "2024-05-10T08:00:02.000Z",
+    "endTime": "2024-05-10T08:00:12.000Z",
+    "steps": [
+      {
+        "stepIndex": 1,
+        "stepName": "open-home",
+        "status": "succeeded",
+        "startTime": "2024-05-10T08:00:02.000Z",
+        "endTime": "2024-05-10T08:00:05.500Z",
+        "screenshots": [
+          {
+            "fileName": "01-open-home-succeeded.png",
+            "pageUrl": "https://example.com/",
+            "visualCompareResult": {
+              "baseRunId": "1a2b3c4d-0000-4000-8000-000000000000",
+              "result": "FAILED",
+              "variance": 12.5
+            }
+          }
+        ]
+      },
+      {
+        "stepIndex": 2,
+        "stepName": "checkout",
+        "status": "failed",
+        "failureReason": "Waiting for selector #buy failed",
+        "startTime": "2024-05-10T08:00:05.500Z",
+        "endTime": "2024-05-10T08:00:12.000Z"
+      }
+    ]
+  }
+}`
+
+func TestReportSteps(t *testing.T) {
+	report := &Report{}
+	err := json.Unmarshal([]byte(reportSample), report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := report.GetSteps()
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, found %d", len(steps))
+	}
+
+	tests := []struct {
+		name     string
+		passed   bool
+		duration string
+		reason   string
+	}{
+		{"open-home", true, "3.5s", ""},
+		{"checkout", false, "6.5s", "Waiting for selector #buy failed"},
+	}
+	for i, test := range tests {
+		step := steps[i]
+		if step.Name != test.name {
+			t.Errorf("step %d: expected name %s, found %s", i, test.name, step.Name)
+		}
+		if step.IsPassed() != test.passed {
+			t.Errorf("step %s: expected passed %t, found status %s", step.Name, test.passed, step.Status)
+		}
+		if step.Duration().String() != test.duration {
+			t.Errorf("step %s: expected duration %s, found %s", step.Name, test.duration, step.Duration())
+		}
+		if step.FailureReason != test.reason {
+			t.Errorf("step %s: expected reason %q, found %q", step.Name, test.reason, step.FailureReason)
+		}
+	}
+
+	screenshot := steps[0].Screenshots[0]
+	if screenshot.IsVisualComparisonFailed() == false {
+		t.Error("expected visual comparison to be failed")
+	}
+	if variance := screenshot.GetVisualVariance(); variance != "12.5" {
+		t.Errorf("expected variance 12.5, found %s", variance)
+	}
+}
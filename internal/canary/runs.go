@@ -0,0 +1,54 @@
+package canary
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/synthetics"
+)
+
+// RunsFilter structure
+type RunsFilter struct {
+	Since    time.Time
+	Until    time.Time
+	State    string
+	Limit    int
+	AllPages bool
+}
+
+// ListRuns return canary runs that match the filter, newest first. Only the first page with runs
+// started before the until time is returned, unless all pages are requested or a since time is set
+func (c *Canary) ListRuns(ctx context.Context, filter *RunsFilter) ([]*synthetics.CanaryRun, error) {
+	runs := []*synthetics.CanaryRun{}
+	followPages := filter.AllPages || filter.Since.IsZero() == false
+	reachedUntil := filter.Until.IsZero()
+
+	err := c.GetRunsPages(ctx, func(page []*synthetics.CanaryRun) bool {
+		for _, run := range page {
+			started := time.Time{}
+			if run.Timeline != nil && run.Timeline.Started != nil {
+				started = *run.Timeline.Started
+			}
+
+			// Runs are sorted newest first, stop at the first older run
+			if filter.Since.IsZero() == false && started.Before(filter.Since) {
+				return false
+			}
+			if filter.Until.IsZero() == false && started.After(filter.Until) {
+				continue
+			}
+			reachedUntil = true
+			if len(filter.State) > 0 && *run.Status.State != filter.State {
+				continue
+			}
+
+			runs = append(runs, run)
+			if filter.Limit > 0 && len(runs) >= filter.Limit {
+				return false
+			}
+		}
+		return followPages || reachedUntil == false
+	})
+
+	return runs, err
+}
@@ -0,0 +1,106 @@
+package canary
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestListRuns(t *testing.T) {
+	backend := fake.NewBackend()
+	base := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// 250 runs, one per minute, newest first
+	runs := []*synthetics.CanaryRun{}
+	for i := 0; i < 250; i++ {
+		runs = append(runs, &synthetics.CanaryRun{
+			Id:       aws.String(fmt.Sprintf("run-%d", i)),
+			Status:   &synthetics.CanaryRunStatus{State: aws.String(synthetics.CanaryRunStatePassed)},
+			Timeline: &synthetics.CanaryRunTimeline{Started: aws.Time(base.Add(-time.Duration(i) * time.Minute))},
+		})
+	}
+	backend.Canaries["test"] = &fake.Canary{
+		Canary: &synthetics.Canary{
+			Name:   aws.String("test"),
+			Status: &synthetics.CanaryStatus{State: aws.String(synthetics.CanaryStateReady)},
+		},
+		Runs: runs,
+	}
+
+	tests := []struct {
+		name   string
+		filter *RunsFilter
+		count  int
+		first  string
+	}{
+		{"first page", &RunsFilter{}, 100, "run-0"},
+		{"all pages", &RunsFilter{AllPages: true}, 250, "run-0"},
+		{"since", &RunsFilter{Since: base.Add(-120 * time.Minute)}, 121, "run-0"},
+		{"until on a later page", &RunsFilter{Until: base.Add(-150 * time.Minute)}, 50, "run-150"},
+		{"until and since", &RunsFilter{Since: base.Add(-220 * time.Minute), Until: base.Add(-150 * time.Minute)}, 71, "run-150"},
+		{"until and limit", &RunsFilter{Until: base.Add(-150 * time.Minute), Limit: 10}, 10, "run-150"},
+	}
+
+	c := New(fake.NewClients(backend), "test")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			found, err := c.ListRuns(context.Background(), test.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(found) != test.count {
+				t.Fatalf("expected %d runs, found %d", test.count, len(found))
+			}
+			if *found[0].Id != test.first {
+				t.Errorf("expected first run %s, found %s", test.first, *found[0].Id)
+			}
+		})
+	}
+}
+
+func TestWaitRunWithDeletedPreviousRun(t *testing.T) {
+	backend := fake.NewBackend()
+	base := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// Triggered run followed by 150 older runs, previous run is no longer listed
+	runs := []*synthetics.CanaryRun{
+		{
+			Id:       aws.String("triggered"),
+			Status:   &synthetics.CanaryRunStatus{State: aws.String(synthetics.CanaryRunStatePassed)},
+			Timeline: &synthetics.CanaryRunTimeline{Started: aws.Time(base.Add(time.Minute))},
+		},
+	}
+	for i := 1; i <= 150; i++ {
+		runs = append(runs, &synthetics.CanaryRun{
+			Id:       aws.String(fmt.Sprintf("run-%d", i)),
+			Status:   &synthetics.CanaryRunStatus{State: aws.String(synthetics.CanaryRunStatePassed)},
+			Timeline: &synthetics.CanaryRunTimeline{Started: aws.Time(base.Add(-time.Duration(i) * time.Minute))},
+		})
+	}
+	backend.Canaries["test"] = &fake.Canary{
+		Canary: &synthetics.Canary{
+			Name:   aws.String("test"),
+			Status: &synthetics.CanaryStatus{State: aws.String(synthetics.CanaryStateReady)},
+		},
+		Runs: runs,
+	}
+	previousRun := &synthetics.CanaryRun{
+		Id:       aws.String("deleted"),
+		Timeline: &synthetics.CanaryRunTimeline{Started: aws.Time(base)},
+	}
+
+	c := New(fake.NewClients(backend), "test")
+	c.pollInterval = time.Millisecond
+	run, err := c.WaitRun(context.Background(), previousRun)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *run.Id != "triggered" {
+		t.Errorf("expected triggered run, found %s", *run.Id)
+	}
+}
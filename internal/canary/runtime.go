@@ -0,0 +1,143 @@
+package canary
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+)
+
+// Runtime structure
+type Runtime struct {
+	Name            string
+	Description     string
+	ReleaseDate     *time.Time
+	DeprecationDate *time.Time
+}
+
+// ListRuntimes return Synthetics runtimes available in current AWS account and region,
+// sorted by family and newest version first
+func ListRuntimes(ctx context.Context, clients *awsinternal.Clients) ([]*Runtime, error) {
+	runtimes := []*Runtime{}
+
+	var nextToken *string
+	for {
+		res, err := clients.Synthetics.DescribeRuntimeVersionsWithContext(ctx, &synthetics.DescribeRuntimeVersionsInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return runtimes, err
+		}
+
+		for _, version := range res.RuntimeVersions {
+			runtime := &Runtime{
+				Name:            *version.VersionName,
+				ReleaseDate:     version.ReleaseDate,
+				DeprecationDate: version.DeprecationDate,
+			}
+			if version.Description != nil {
+				runtime.Description = *version.Description
+			}
+			runtimes = append(runtimes, runtime)
+		}
+
+		if res.NextToken == nil {
+			break
+		}
+		nextToken = res.NextToken
+	}
+
+	sort.SliceStable(runtimes, func(i, j int) bool {
+		if runtimes[i].GetFamily() != runtimes[j].GetFamily() {
+			return runtimes[i].GetFamily() < runtimes[j].GetFamily()
+		}
+		return compareRuntimeVersions(runtimes[i].GetVersion(), runtimes[j].GetVersion()) > 0
+	})
+
+	return runtimes, nil
+}
+
+// FindRuntime return the runtime with the provided name, nil when not found
+func FindRuntime(runtimes []*Runtime, name string) *Runtime {
+	for _, runtime := range runtimes {
+		if runtime.Name == name {
+			return runtime
+		}
+	}
+	return nil
+}
+
+// FindLatestRuntime return the latest not deprecated runtime of the provided family, nil when not found
+func FindLatestRuntime(runtimes []*Runtime, family string) *Runtime {
+	var latest *Runtime
+	for _, runtime := range runtimes {
+		if runtime.GetFamily() != family || runtime.IsDeprecated() {
+			continue
+		}
+		if latest == nil || compareRuntimeVersions(runtime.GetVersion(), latest.GetVersion()) > 0 {
+			latest = runtime
+		}
+	}
+	return latest
+}
+
+// GetFamily return runtime name without version, for example syn-nodejs-puppeteer
+func (r *Runtime) GetFamily() string {
+	return getRuntimeFamily(r.Name)
+}
+
+// GetVersion return runtime version, for example 3.9
+func (r *Runtime) GetVersion() string {
+	return strings.TrimPrefix(r.Name[len(r.GetFamily()):], "-")
+}
+
+// IsDeprecated check if runtime is already deprecated
+func (r *Runtime) IsDeprecated() bool {
+	return r.DeprecationDate != nil && r.DeprecationDate.Before(time.Now())
+}
+
+// IsDeprecating check if runtime has a deprecation date in the future
+func (r *Runtime) IsDeprecating() bool {
+	return r.DeprecationDate != nil && r.DeprecationDate.After(time.Now())
+}
+
+// GetRuntimeFamily return canary runtime name without version
+func (c *Canary) GetRuntimeFamily() string {
+	return getRuntimeFamily(c.RuntimeVersion)
+}
+
+func getRuntimeFamily(name string) string {
+	index := strings.LastIndex(name, "-")
+	if index == -1 {
+		return name
+	}
+	return name[:index]
+}
+
+func compareRuntimeVersions(a string, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aNumber, bNumber := 0, 0
+		if i < len(aParts) {
+			aNumber, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bNumber, _ = strconv.Atoi(bParts[i])
+		}
+		if aNumber != bNumber {
+			return aNumber - bNumber
+		}
+	}
+	return 0
+}
+
+// CompareRuntimes compare versions of runtimes of the same family,
+// return a positive number when a is newer than b, negative when older and zero when equal
+func CompareRuntimes(a *Runtime, b *Runtime) int {
+	return compareRuntimeVersions(a.GetVersion(), b.GetVersion())
+}
@@ -0,0 +1,78 @@
+package canary
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareRuntimes(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"syn-nodejs-puppeteer-3.9", "syn-nodejs-puppeteer-3.9", 0},
+		{"syn-nodejs-puppeteer-3.10", "syn-nodejs-puppeteer-3.9", 1},
+		{"syn-nodejs-puppeteer-3.9", "syn-nodejs-puppeteer-6.2", -1},
+		{"syn-nodejs-puppeteer-7.0", "syn-nodejs-puppeteer-7", 0},
+		{"syn-python-selenium-1.3", "syn-python-selenium-1.0.1", 1},
+	}
+
+	for _, test := range tests {
+		result := CompareRuntimes(&Runtime{Name: test.a}, &Runtime{Name: test.b})
+		if sign(result) != test.expected {
+			t.Errorf("%s vs %s: expected %d, found %d", test.a, test.b, test.expected, result)
+		}
+	}
+}
+
+func TestFindLatestRuntime(t *testing.T) {
+	past := time.Now().Add(-24 * time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+	runtimes := []*Runtime{
+		{Name: "syn-nodejs-puppeteer-3.9", DeprecationDate: &past},
+		{Name: "syn-nodejs-puppeteer-6.2", DeprecationDate: &future},
+		{Name: "syn-nodejs-puppeteer-10.0", DeprecationDate: &past},
+		{Name: "syn-nodejs-puppeteer-7.0"},
+		{Name: "syn-python-selenium-1.3", DeprecationDate: &past},
+	}
+
+	tests := []struct {
+		family   string
+		expected string
+	}{
+		{"syn-nodejs-puppeteer", "syn-nodejs-puppeteer-7.0"},
+		{"syn-python-selenium", ""},
+		{"syn-nodejs-playwright", ""},
+	}
+
+	for _, test := range tests {
+		latest := FindLatestRuntime(runtimes, test.family)
+		name := ""
+		if latest != nil {
+			name = latest.Name
+		}
+		if name != test.expected {
+			t.Errorf("%s: expected %q, found %q", test.family, test.expected, name)
+		}
+	}
+}
+
+func TestRuntimeFamilyAndVersion(t *testing.T) {
+	runtime := &Runtime{Name: "syn-nodejs-puppeteer-3.9"}
+	if family := runtime.GetFamily(); family != "syn-nodejs-puppeteer" {
+		t.Errorf("expected family syn-nodejs-puppeteer, found %s", family)
+	}
+	if version := runtime.GetVersion(); version != "3.9" {
+		t.Errorf("expected version 3.9, found %s", version)
+	}
+}
+
+func sign(value int) int {
+	if value > 0 {
+		return 1
+	} else if value < 0 {
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+package canary
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+)
+
+// VisualBaselineLastRun is the special baseline run id that select the last canary run
+const VisualBaselineLastRun = "lastrun"
+
+// VisualScreenshot configuration
+type VisualScreenshot struct {
+	Name   string   `yaml:"name" json:"name"`
+	Ignore []string `yaml:"ignore" json:"ignore"`
+}
+
+// VisualConfig configuration
+type VisualConfig struct {
+	Baseline    string             `yaml:"baseline" json:"baseline"`
+	Screenshots []VisualScreenshot `yaml:"screenshots" json:"screenshots"`
+}
+
+// GetVisualReference return current visual monitoring baseline, nil when not set
+func (c *Canary) GetVisualReference(ctx context.Context) (*synthetics.VisualReferenceOutput_, error) {
+	res, err := c.clients.Synthetics.GetCanaryWithContext(ctx, &synthetics.GetCanaryInput{
+		Name: &c.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Canary.VisualReference, nil
+}
+
+// SetVisualBaseline set the run used as visual monitoring baseline
+func (c *Canary) SetVisualBaseline(ctx context.Context, runID string) error {
+	_, err := c.clients.Synthetics.UpdateCanaryWithContext(ctx, &synthetics.UpdateCanaryInput{
+		Name:            &c.Name,
+		VisualReference: c.buildVisualReference(runID),
+	})
+	return err
+}
+
+// GetLastPassedRun return the latest passed canary run
+func (c *Canary) GetLastPassedRun(ctx context.Context) (*synthetics.CanaryRun, error) {
+	runs, err := c.ListRuns(ctx, &RunsFilter{
+		State:    synthetics.CanaryRunStatePassed,
+		Limit:    1,
+		AllPages: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(runs) == 0 {
+		return nil, errors.New("No passed runs found for canary")
+	}
+
+	return runs[0], nil
+}
+
+// getVisualReferenceInput return visual reference to deploy, nil when not configured
+// or when no baseline is configured nor already set
+func (c *Canary) getVisualReferenceInput(ctx context.Context) (*synthetics.VisualReferenceInput_, error) {
+	if c.Visual == nil {
+		return nil, nil
+	}
+
+	// Preserve current baseline when not configured
+	baseline := c.Visual.Baseline
+	if len(baseline) == 0 {
+		current, err := c.GetVisualReference(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil || current.BaseCanaryRunId == nil {
+			return nil, nil
+		}
+		baseline = *current.BaseCanaryRunId
+	}
+
+	return c.buildVisualReference(baseline), nil
+}
+
+func (c *Canary) buildVisualReference(runID string) *synthetics.VisualReferenceInput_ {
+	reference := &synthetics.VisualReferenceInput_{
+		BaseCanaryRunId: aws.String(runID),
+	}
+	if c.Visual == nil {
+		return reference
+	}
+
+	for _, screenshot := range c.Visual.Screenshots {
+		reference.BaseScreenshots = append(reference.BaseScreenshots, &synthetics.BaseScreenshot{
+			ScreenshotName:    aws.String(screenshot.Name),
+			IgnoreCoordinates: aws.StringSlice(screenshot.Ignore),
+		})
+	}
+
+	return reference
+}
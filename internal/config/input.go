@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/aws/aws-sdk-go/service/synthetics"
@@ -93,7 +94,7 @@ func AskSingleCanaryRun(runs []*synthetics.CanaryRun) (*synthetics.CanaryRun, er
 	header := fmt.Sprintf("%-36s\t%-7s\t%-25s\t%-25s", "Id", "Status", "Started At", "Compleated At")
 	var options []string
 	for _, run := range runs {
-		options = append(options, fmt.Sprintf("%-36s\t%-7s\t%-25s\t%-25s", *run.Id, *run.Status.State, *run.Timeline.Started, *run.Timeline.Completed))
+		options = append(options, fmt.Sprintf("%-36s\t%-7s\t%-25s\t%-25s", *run.Id, *run.Status.State, FormatRunTime(run.Timeline.Started), FormatRunTime(run.Timeline.Completed)))
 	}
 
 	// Ask selection
@@ -113,3 +114,11 @@ func AskSingleCanaryRun(runs []*synthetics.CanaryRun) (*synthetics.CanaryRun, er
 
 	return runs[canaryRunIndex], nil
 }
+
+// FormatRunTime format a run timeline time, runs still in progress have no completion time
+func FormatRunTime(date *time.Time) string {
+	if date == nil {
+		return "-"
+	}
+	return date.String()
+}
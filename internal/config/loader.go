@@ -4,12 +4,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/daaru00/aws-canary-cli/internal/aws"
 	"github.com/daaru00/aws-canary-cli/internal/canary"
 	"github.com/joho/godotenv"
 	"github.com/urfave/cli/v2"
@@ -36,7 +37,7 @@ func LoadDotEnv() error {
 }
 
 // LoadCanaries load canary using user input
-func LoadCanaries(c *cli.Context, ses *session.Session) (*[]*canary.Canary, error) {
+func LoadCanaries(c *cli.Context, clients *aws.Clients) (*[]*canary.Canary, error) {
 	canaries := []*canary.Canary{}
 
 	// Search config in sources
@@ -67,19 +68,26 @@ func LoadCanaries(c *cli.Context, ses *session.Session) (*[]*canary.Canary, erro
 		fileMode := info.Mode()
 		if fileMode.IsDir() {
 			// Found canary in directory
-			canariesFound, err := LoadCanariesFromDir(ses, &searchPath, &fileName, &parser)
+			canariesFound, err := LoadCanariesFromDir(clients, &searchPath, &fileName, &parser)
 			if err != nil {
 				return nil, err
 			}
 
+			// Set configuration root
+			configRoot := GetConfigRoot(searchPath)
+			for _, canaryFound := range canariesFound {
+				canaryFound.ConfigRoot = configRoot
+			}
+
 			// Append canaries
 			canaries = append(canaries, canariesFound...)
 		} else if fileMode.IsRegular() {
 			// Load canary from file
-			canaryFound, err := LoadCanaryFromFile(ses, &searchPath, &parser)
+			canaryFound, err := LoadCanaryFromFile(clients, &searchPath, &parser)
 			if err != nil {
 				return nil, err
 			}
+			canaryFound.ConfigRoot = GetConfigRoot(filepath.Dir(searchPath))
 
 			// Append canaries
 			canaries = append(canaries, canaryFound)
@@ -88,11 +96,33 @@ func LoadCanaries(c *cli.Context, ses *session.Session) (*[]*canary.Canary, erro
 		}
 	}
 
+	// Set default project
+	project := c.String("project")
+	for _, canary := range canaries {
+		if len(canary.Project) == 0 {
+			canary.Project = project
+		}
+	}
+
 	return &canaries, nil
 }
 
+// GetConfigRoot return the git repository top level directory containing dir,
+// when dir is not part of a git repository dir itself is returned
+func GetConfigRoot(dir string) string {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		return dir
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
 // LoadCanaryFromFile load canary from file
-func LoadCanaryFromFile(ses *session.Session, filePath *string, parser *string) (*canary.Canary, error) {
+func LoadCanaryFromFile(clients *aws.Clients, filePath *string, parser *string) (*canary.Canary, error) {
 	// If file match read content
 	fileContent, err := ioutil.ReadFile(*filePath)
 	if err != nil {
@@ -106,13 +136,14 @@ func LoadCanaryFromFile(ses *session.Session, filePath *string, parser *string)
 	fileName := filepath.Base(*filePath)
 	extension := filepath.Ext(fileName)
 	canaryName := fileName[0 : len(fileName)-len(extension)]
-	canary := canary.New(ses, canaryName)
+	canary := canary.New(clients, canaryName)
 	err = ParseContent(fileContentInterpolated, parser, canary)
 	if err != nil {
 		return nil, err
 	}
 
 	// Add path to config
+	canary.ConfigPath = *filePath
 	if len(canary.Code.Src) == 0 {
 		canary.Code.Src = filepath.Dir(*filePath)
 	} else {
@@ -134,7 +165,7 @@ func LoadCanaryFromFile(ses *session.Session, filePath *string, parser *string)
 }
 
 // LoadCanariesFromDir search config files and load canaries
-func LoadCanariesFromDir(ses *session.Session, searchPath *string, fileNameToMatch *string, parser *string) ([]*canary.Canary, error) {
+func LoadCanariesFromDir(clients *aws.Clients, searchPath *string, fileNameToMatch *string, parser *string) ([]*canary.Canary, error) {
 	start := time.Now()
 	filesCount := 0
 	canaries := []*canary.Canary{}
@@ -159,7 +190,7 @@ func LoadCanariesFromDir(ses *session.Session, searchPath *string, fileNameToMat
 		}
 
 		// Parse canary from file
-		canary, err := LoadCanaryFromFile(ses, &filePath, parser)
+		canary, err := LoadCanaryFromFile(clients, &filePath, parser)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/daaru00/aws-canary-cli/internal/fake"
+)
+
+func TestRelativeConfigPath(t *testing.T) {
+	clients := fake.NewClients(fake.NewBackend())
+	dir := t.TempDir()
+	canaryDir, err := fake.WriteCanary(path.Join(dir, "canaries"), "test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configPath := path.Join(canaryDir, "canary.yml")
+
+	// Without git the search path is the root
+	if root := GetConfigRoot(path.Join(dir, "canaries")); root != path.Join(dir, "canaries") {
+		t.Errorf("expected root %s, found %s", path.Join(dir, "canaries"), root)
+	}
+	c := canary.New(clients, "test")
+	c.ConfigPath = configPath
+	c.ConfigRoot = GetConfigRoot(path.Join(dir, "canaries"))
+	if relPath := c.GetRelativeConfigPath(); relPath != "test/canary.yml" {
+		t.Errorf("expected config path test/canary.yml, found %s", relPath)
+	}
+
+	// With git the repository top level is the root, whatever the search path or the working directory
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	err = exec.Command("git", "init", "-q", dir).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+	err = os.Chdir(canaryDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.ConfigPath = "canary.yml"
+	c.ConfigRoot = GetConfigRoot(".")
+	resolvedDir, _ := filepath.EvalSymlinks(dir)
+	if resolvedRoot, _ := filepath.EvalSymlinks(c.ConfigRoot); resolvedRoot != resolvedDir {
+		t.Errorf("expected root %s, found %s", resolvedDir, c.ConfigRoot)
+	}
+	if relPath := c.GetRelativeConfigPath(); relPath != "canaries/test/canary.yml" {
+		t.Errorf("expected config path canaries/test/canary.yml, found %s", relPath)
+	}
+}
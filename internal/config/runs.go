@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+	"github.com/urfave/cli/v2"
+)
+
+// Relative durations with days support, for example 7d or 1d12h
+var durationRegexp = regexp.MustCompile(`^(\d+)d(.*)$`)
+
+// Dates formats accepted as absolute time
+var timeFormats = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// RunsFilterFlags return flags used to filter canary runs
+func RunsFilterFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "since",
+			Usage: "Return runs started after a relative duration (for example 24h or 7d) or a date (for example 2021-03-01)",
+		},
+		&cli.StringFlag{
+			Name:  "until",
+			Usage: "Return runs started before a relative duration (for example 24h or 7d) or a date (for example 2021-03-01)",
+		},
+		&cli.StringFlag{
+			Name:  "state",
+			Usage: "Return runs in state PASSED, FAILED or RUNNING",
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "Maximum number of runs returned (0 means no limit)",
+		},
+		&cli.BoolFlag{
+			Name:  "all-pages",
+			Usage: "Follow all runs pages, by default only the first page with runs started before until is returned unless since is set",
+		},
+	}
+}
+
+// GetRunsFilter return canary runs filter from user input
+func GetRunsFilter(c *cli.Context) (*canary.RunsFilter, error) {
+	var err error
+	now := time.Now()
+	filter := &canary.RunsFilter{
+		State:    c.String("state"),
+		Limit:    c.Int("limit"),
+		AllPages: c.Bool("all-pages"),
+	}
+
+	// Parse time range
+	if len(c.String("since")) > 0 {
+		filter.Since, err = ParseTime(c.String("since"), now)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(c.String("until")) > 0 {
+		filter.Until, err = ParseTime(c.String("until"), now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Check state
+	switch filter.State {
+	case "", synthetics.CanaryRunStatePassed, synthetics.CanaryRunStateFailed, synthetics.CanaryRunStateRunning:
+	default:
+		return nil, fmt.Errorf("Run state %s not supported, valid states are PASSED, FAILED and RUNNING", filter.State)
+	}
+
+	return filter, nil
+}
+
+// ParseDuration parse a duration that also support days, for example 7d or 1d12h
+func ParseDuration(value string) (time.Duration, error) {
+	match := durationRegexp.FindStringSubmatch(value)
+	if match == nil {
+		return time.ParseDuration(value)
+	}
+
+	days, _ := strconv.Atoi(match[1])
+	duration := time.Duration(days) * 24 * time.Hour
+	if len(match[2]) > 0 {
+		rest, err := time.ParseDuration(match[2])
+		if err != nil {
+			return 0, fmt.Errorf("Invalid duration %s", value)
+		}
+		duration += rest
+	}
+
+	return duration, nil
+}
+
+// ParseTime parse a date or a duration relative to now, for example 7d means 7 days ago
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	for _, format := range timeFormats {
+		parsed, err := time.ParseInLocation(format, value, time.Local)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+
+	duration, err := ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid time %s, use a relative duration (for example 24h or 7d) or a date (for example 2021-03-01)", value)
+	}
+
+	return now.Add(-duration), nil
+}
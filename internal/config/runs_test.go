@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		fail     bool
+	}{
+		{"30m", 30 * time.Minute, false},
+		{"24h", 24 * time.Hour, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{"2d30m", 48*time.Hour + 30*time.Minute, false},
+		{"0d", 0, false},
+		{"1dx", 0, true},
+		{"week", 0, true},
+		{"", 0, true},
+	}
+
+	for _, test := range tests {
+		duration, err := ParseDuration(test.value)
+		if test.fail {
+			if err == nil {
+				t.Errorf("%q: expected error, found %s", test.value, duration)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %s", test.value, err)
+			continue
+		}
+		if duration != test.expected {
+			t.Errorf("%q: expected %s, found %s", test.value, test.expected, duration)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2021, 3, 10, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+		fail     bool
+	}{
+		{"24h", now.Add(-24 * time.Hour), false},
+		{"7d", now.Add(-7 * 24 * time.Hour), false},
+		{"2021-03-01", time.Date(2021, 3, 1, 0, 0, 0, 0, time.Local), false},
+		{"2021-03-01 08:30", time.Date(2021, 3, 1, 8, 30, 0, 0, time.Local), false},
+		{"2021-03-01 08:30:15", time.Date(2021, 3, 1, 8, 30, 15, 0, time.Local), false},
+		{"2021-03-01T08:30:15", time.Date(2021, 3, 1, 8, 30, 15, 0, time.Local), false},
+		{"2021-03-01T08:30:15Z", time.Date(2021, 3, 1, 8, 30, 15, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+		{"2021-13-01", time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		parsed, err := ParseTime(test.value, now)
+		if test.fail {
+			if err == nil {
+				t.Errorf("%q: expected error, found %s", test.value, parsed)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %s", test.value, err)
+			continue
+		}
+		if parsed.Equal(test.expected) == false {
+			t.Errorf("%q: expected %s, found %s", test.value, test.expected, parsed)
+		}
+	}
+}
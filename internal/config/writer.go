@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	yamlRuntimeRegex = regexp.MustCompile(`(?m)^runtime:[ \t]*("[^"\n]*"|'[^'\n]*'|[^\s#]*)`)
+	yamlNameRegex    = regexp.MustCompile(`(?m)^name:.*$`)
+	jsonRuntimeRegex = regexp.MustCompile(`"runtime"\s*:\s*"[^"]*"`)
+	jsonObjectRegex  = regexp.MustCompile(`^\s*\{`)
+)
+
+// UpdateRuntime rewrite runtime value in configuration file, preserving other content
+func UpdateRuntime(filePath string, parser string, runtime string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	fileContent, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	content := string(fileContent)
+
+	// Replace or add runtime value
+	switch parser {
+	case "json":
+		if match := jsonRuntimeRegex.FindString(content); len(match) > 0 {
+			if strings.Contains(match, "${") {
+				return fmt.Errorf("Runtime in %s is interpolated from environment variables, update it manually", filePath)
+			}
+			content = jsonRuntimeRegex.ReplaceAllLiteralString(content, fmt.Sprintf(`"runtime": %q`, runtime))
+		} else if jsonObjectRegex.MatchString(content) {
+			content = jsonObjectRegex.ReplaceAllLiteralString(content, fmt.Sprintf("{\n  \"runtime\": %q,", runtime))
+		} else {
+			return fmt.Errorf("Cannot find runtime in %s, update it manually", filePath)
+		}
+	default:
+		if match := yamlRuntimeRegex.FindString(content); len(match) > 0 {
+			if strings.Contains(match, "${") {
+				return fmt.Errorf("Runtime in %s is interpolated from environment variables, update it manually", filePath)
+			}
+			content = yamlRuntimeRegex.ReplaceAllLiteralString(content, "runtime: "+runtime)
+		} else if match := yamlNameRegex.FindStringIndex(content); match != nil {
+			content = content[:match[1]] + "\nruntime: " + runtime + content[match[1]:]
+		} else {
+			content = "runtime: " + runtime + "\n" + content
+		}
+	}
+
+	return ioutil.WriteFile(filePath, []byte(content), info.Mode())
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestUpdateRuntime(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   string
+		content  string
+		expected string
+		fail     bool
+	}{
+		{
+			name:     "yaml replace",
+			parser:   "yml",
+			content:  "name: test\nruntime: syn-nodejs-puppeteer-3.9 # current runtime\nmemory: 1000\n",
+			expected: "name: test\nruntime: syn-nodejs-puppeteer-7.0 # current runtime\nmemory: 1000\n",
+		},
+		{
+			name:     "yaml quoted",
+			parser:   "yml",
+			content:  "name: test\nruntime: \"syn-nodejs-puppeteer-3.9\"\n",
+			expected: "name: test\nruntime: syn-nodejs-puppeteer-7.0\n",
+		},
+		{
+			name:     "yaml add after name",
+			parser:   "yml",
+			content:  "name: test\nmemory: 1000\n",
+			expected: "name: test\nruntime: syn-nodejs-puppeteer-7.0\nmemory: 1000\n",
+		},
+		{
+			name:     "yaml nested runtime key untouched",
+			parser:   "yml",
+			content:  "name: test\nenv:\n  runtime: keep\n",
+			expected: "name: test\nruntime: syn-nodejs-puppeteer-7.0\nenv:\n  runtime: keep\n",
+		},
+		{
+			name:    "yaml interpolated",
+			parser:  "yml",
+			content: "name: test\nruntime: ${RUNTIME}\n",
+			fail:    true,
+		},
+		{
+			name:     "json replace",
+			parser:   "json",
+			content:  "{\n  \"name\": \"test\",\n  \"runtime\": \"syn-nodejs-puppeteer-3.9\"\n}\n",
+			expected: "{\n  \"name\": \"test\",\n  \"runtime\": \"syn-nodejs-puppeteer-7.0\"\n}\n",
+		},
+		{
+			name:     "json add",
+			parser:   "json",
+			content:  "{\n  \"name\": \"test\"\n}\n",
+			expected: "{\n  \"runtime\": \"syn-nodejs-puppeteer-7.0\",\n  \"name\": \"test\"\n}\n",
+		},
+		{
+			name:    "json interpolated",
+			parser:  "json",
+			content: "{\"runtime\": \"${RUNTIME}\"}",
+			fail:    true,
+		},
+		{
+			name:    "json not an object",
+			parser:  "json",
+			content: "[]",
+			fail:    true,
+		},
+	}
+
+	for _, test := range tests {
+		filePath := path.Join(t.TempDir(), "canary."+test.parser)
+		err := ioutil.WriteFile(filePath, []byte(test.content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = UpdateRuntime(filePath, test.parser, "syn-nodejs-puppeteer-7.0")
+		content, _ := ioutil.ReadFile(filePath)
+		if test.fail {
+			if err == nil {
+				t.Errorf("%s: expected error", test.name)
+			}
+			if string(content) != test.content {
+				t.Errorf("%s: expected file untouched, found %q", test.name, content)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err)
+			continue
+		}
+		if string(content) != test.expected {
+			t.Errorf("%s: expected:\n%s\nfound:\n%s", test.name, test.expected, content)
+		}
+		if strings.Count(string(content), "syn-nodejs-puppeteer-7.0") != 1 {
+			t.Errorf("%s: expected runtime written once", test.name)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+)
+
+// Layout templates
+const (
+	TemplateRows     = "rows"
+	TemplateCompact  = "compact"
+	TemplateOverview = "overview"
+)
+
+// Templates contains supported layout templates
+var Templates = []string{TemplateRows, TemplateCompact, TemplateOverview}
+
+// Dashboard grid width
+const gridWidth = 24
+
+// Config configuration
+type Config struct {
+	Name     string `yaml:"name" json:"name"`
+	Template string `yaml:"template" json:"template"`
+}
+
+// Validate check dashboard configuration
+func (c *Config) Validate() error {
+	if len(c.Template) == 0 {
+		return nil
+	}
+	for _, template := range Templates {
+		if c.Template == template {
+			return nil
+		}
+	}
+	return fmt.Errorf("Dashboard template %s not supported, valid templates are %s", c.Template, strings.Join(Templates, ", "))
+}
+
+// Member structure, a canary shown in dashboard
+type Member struct {
+	Canary   string
+	LogGroup string
+	Alarms   []string
+}
+
+// Dashboard structure
+type Dashboard struct {
+	clients *awsinternal.Clients
+
+	Name     *string
+	Template string
+}
+
+// Widget structure
+type Widget struct {
+	Type       string                 `json:"type"`
+	X          int                    `json:"x"`
+	Y          int                    `json:"y"`
+	Width      int                    `json:"width"`
+	Height     int                    `json:"height"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Body structure
+type Body struct {
+	Widgets []*Widget `json:"widgets"`
+}
+
+// New creates a new Dashboard
+func New(clients *awsinternal.Clients, name *string) *Dashboard {
+	return &Dashboard{
+		clients: clients,
+
+		Name:     name,
+		Template: TemplateRows,
+	}
+}
+
+// IsDeployed check if CloudWatch Dashboard is present in current AWS account
+func (d *Dashboard) IsDeployed(ctx context.Context) bool {
+	_, err := d.clients.CloudWatch.GetDashboardWithContext(ctx, &cloudwatch.GetDashboardInput{
+		DashboardName: d.Name,
+	})
+	return err == nil
+}
+
+// GetCanaryNames return the names of canaries shown in deployed dashboard
+func (d *Dashboard) GetCanaryNames(ctx context.Context) ([]string, error) {
+	names := []string{}
+
+	res, err := d.clients.CloudWatch.GetDashboardWithContext(ctx, &cloudwatch.GetDashboardInput{
+		DashboardName: d.Name,
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == cloudwatch.ErrCodeResourceNotFound {
+			return names, nil
+		}
+		return names, err
+	}
+
+	body := &Body{}
+	err = json.Unmarshal([]byte(aws.StringValue(res.DashboardBody)), body)
+	if err != nil {
+		return names, err
+	}
+
+	// Search canary dimension in metric widgets
+	found := map[string]bool{}
+	for _, widget := range body.Widgets {
+		metrics, ok := widget.Properties["metrics"].([]interface{})
+		if widget.Type != "metric" || !ok {
+			continue
+		}
+		for _, metric := range metrics {
+			values, ok := metric.([]interface{})
+			if !ok || len(values) < 4 || values[0] != awsinternal.MetricsNamespace || values[2] != "CanaryName" {
+				continue
+			}
+			name, ok := values[3].(string)
+			if ok && found[name] == false {
+				found[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Build return dashboard body for provided members
+func (d *Dashboard) Build(region string, members []*Member) (string, error) {
+	body := &Body{
+		Widgets: []*Widget{},
+	}
+	y := 0
+
+	// Add header
+	canaryNames := []string{}
+	for _, member := range members {
+		canaryNames = append(canaryNames, member.Canary)
+	}
+	body.Widgets = append(body.Widgets, &Widget{
+		Type:   "text",
+		Y:      y,
+		Width:  gridWidth,
+		Height: 2,
+		Properties: map[string]interface{}{
+			"markdown": fmt.Sprintf("# %s\nSynthetics canaries: %s", *d.Name, strings.Join(canaryNames, ", ")),
+		},
+	})
+	y += 2
+
+	// Add alarms status
+	alarms := []string{}
+	for _, member := range members {
+		alarms = append(alarms, member.Alarms...)
+	}
+	if len(alarms) > 0 {
+		body.Widgets = append(body.Widgets, &Widget{
+			Type:   "alarm",
+			Y:      y,
+			Width:  gridWidth,
+			Height: 3,
+			Properties: map[string]interface{}{
+				"title":  "Alarms",
+				"alarms": alarms,
+			},
+		})
+		y += 3
+	}
+
+	// Add metrics
+	switch d.Template {
+	case TemplateRows, "":
+		for _, member := range members {
+			body.Widgets = append(body.Widgets,
+				metricWidget(0, y, gridWidth/2, fmt.Sprintf("%s success percent", member.Canary), region, "SuccessPercent", member.Canary),
+				metricWidget(gridWidth/2, y, gridWidth/2, fmt.Sprintf("%s duration", member.Canary), region, "Duration", member.Canary),
+			)
+			y += 6
+		}
+	case TemplateCompact:
+		for i, member := range members {
+			widget := metricWidget((i%3)*gridWidth/3, y+(i/3)*6, gridWidth/3, member.Canary, region, "SuccessPercent", member.Canary)
+			widget.Properties["metrics"] = append(widget.Properties["metrics"].([]interface{}), []interface{}{
+				awsinternal.MetricsNamespace, "Duration", "CanaryName", member.Canary, map[string]string{"yAxis": "right"},
+			})
+			body.Widgets = append(body.Widgets, widget)
+		}
+		y += ((len(members) + 2) / 3) * 6
+	case TemplateOverview:
+		body.Widgets = append(body.Widgets,
+			metricWidget(0, y, gridWidth, "Success percent", region, "SuccessPercent", canaryNames...),
+			metricWidget(0, y+6, gridWidth, "Duration", region, "Duration", canaryNames...),
+		)
+		y += 12
+	default:
+		return "", fmt.Errorf("Dashboard template %s not supported, valid templates are %s", d.Template, strings.Join(Templates, ", "))
+	}
+
+	// Add logs insights query over canaries log groups
+	sources := []string{}
+	for _, member := range members {
+		if len(member.LogGroup) > 0 {
+			sources = append(sources, fmt.Sprintf("SOURCE '%s'", member.LogGroup))
+		}
+	}
+	if len(sources) > 0 {
+		body.Widgets = append(body.Widgets, &Widget{
+			Type:   "log",
+			Y:      y,
+			Width:  gridWidth,
+			Height: 6,
+			Properties: map[string]interface{}{
+				"title":  "Errors",
+				"region": region,
+				"view":   "table",
+				"query":  strings.Join(sources, " | ") + " | fields @timestamp, @message, @log | filter @message like /(?i)(error|fail)/ | sort @timestamp desc | limit 50",
+			},
+		})
+	}
+
+	content, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// Deploy create or update dashboard
+func (d *Dashboard) Deploy(ctx context.Context, body string) error {
+	_, err := d.clients.CloudWatch.PutDashboardWithContext(ctx, &cloudwatch.PutDashboardInput{
+		DashboardName: d.Name,
+		DashboardBody: aws.String(body),
+	})
+	return err
+}
+
+// Remove dashboard
+func (d *Dashboard) Remove(ctx context.Context) error {
+	_, err := d.clients.CloudWatch.DeleteDashboardsWithContext(ctx, &cloudwatch.DeleteDashboardsInput{
+		DashboardNames: []*string{d.Name},
+	})
+	return err
+}
+
+func metricWidget(x int, y int, width int, title string, region string, metricName string, canaryNames ...string) *Widget {
+	metrics := []interface{}{}
+	for _, canaryName := range canaryNames {
+		metrics = append(metrics, []interface{}{awsinternal.MetricsNamespace, metricName, "CanaryName", canaryName})
+	}
+
+	return &Widget{
+		Type:   "metric",
+		X:      x,
+		Y:      y,
+		Width:  width,
+		Height: 6,
+		Properties: map[string]interface{}{
+			"title":   title,
+			"region":  region,
+			"view":    "timeSeries",
+			"stat":    "Average",
+			"period":  300,
+			"metrics": metrics,
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package fake
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/urfave/cli/v2"
+)
+
+// GlobalFlags return the global flags required by commands
+func GlobalFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "config-file",
+			Value: "canary.yml",
+		},
+		&cli.StringFlag{
+			Name:  "config-parser",
+			Value: "yml",
+		},
+		&cli.IntFlag{
+			Name:  "max-retries",
+			Value: 1,
+		},
+		&cli.StringFlag{
+			Name: "project",
+		},
+	}
+}
+
+// Run execute a command with clients backed by the in-memory backend
+func Run(backend *Backend, newCommand func(globalFlags []cli.Flag) *cli.Command, args ...string) error {
+	awsinternal.NewAwsClients = func(c *cli.Context) *awsinternal.Clients {
+		return NewClients(backend)
+	}
+
+	command := newCommand(GlobalFlags())
+	app := &cli.App{
+		Commands: []*cli.Command{command},
+	}
+	return app.Run(append([]string{"aws-canary", command.Name}, args...))
+}
+
+// CreateBuckets creates the default artifact and sources buckets, avoiding the deploy confirmation prompt
+func (b *Backend) CreateBuckets() {
+	b.Buckets[fmt.Sprintf("cw-syn-results-%s-%s", b.AccountID, b.Region)] = map[string][]byte{}
+	b.Buckets[fmt.Sprintf("cw-syn-sources-%s-%s", b.AccountID, b.Region)] = map[string][]byte{}
+}
+
+// WriteCanary write a NodeJS canary configuration file and its code into a new directory of dir
+func WriteCanary(dir string, name string, config string) (string, error) {
+	canaryDir := path.Join(dir, name)
+	err := os.MkdirAll(canaryDir, 0755)
+	if err != nil {
+		return canaryDir, err
+	}
+
+	err = ioutil.WriteFile(path.Join(canaryDir, "canary.yml"), []byte(fmt.Sprintf("name: %s\n%s", name, config)), 0644)
+	if err != nil {
+		return canaryDir, err
+	}
+
+	err = ioutil.WriteFile(path.Join(canaryDir, "index.js"), []byte("exports.handler = async () => {}\n"), 0644)
+	return canaryDir, err
+}
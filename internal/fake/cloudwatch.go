@@ -0,0 +1,227 @@
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+)
+
+// CloudWatch is an in-memory implementation of the CloudWatch API
+type CloudWatch struct {
+	cloudwatchiface.CloudWatchAPI
+	backend *Backend
+}
+
+// ListMetricsWithContext return a "run" step metric for canaries with runs
+func (cw *CloudWatch) ListMetricsWithContext(ctx aws.Context, input *cloudwatch.ListMetricsInput, opts ...request.Option) (*cloudwatch.ListMetricsOutput, error) {
+	cw.backend.mutex.Lock()
+	defer cw.backend.mutex.Unlock()
+
+	output := &cloudwatch.ListMetricsOutput{}
+	for name, canary := range cw.backend.Canaries {
+		if len(canary.Runs) == 0 || (len(input.Dimensions) > 0 && aws.StringValue(input.Dimensions[0].Value) != name) {
+			continue
+		}
+		output.Metrics = append(output.Metrics, &cloudwatch.Metric{
+			Namespace:  input.Namespace,
+			MetricName: input.MetricName,
+			Dimensions: []*cloudwatch.Dimension{
+				{Name: aws.String("CanaryName"), Value: aws.String(name)},
+				{Name: aws.String("StepName"), Value: aws.String("run")},
+			},
+		})
+	}
+	return output, nil
+}
+
+// GetMetricDataWithContext return metrics computed from canary runs, a datapoint for each run
+func (cw *CloudWatch) GetMetricDataWithContext(ctx aws.Context, input *cloudwatch.GetMetricDataInput, opts ...request.Option) (*cloudwatch.GetMetricDataOutput, error) {
+	cw.backend.mutex.Lock()
+	defer cw.backend.mutex.Unlock()
+
+	output := &cloudwatch.GetMetricDataOutput{}
+	for _, query := range input.MetricDataQueries {
+		result := &cloudwatch.MetricDataResult{
+			Id:         query.Id,
+			StatusCode: aws.String(cloudwatch.StatusCodeComplete),
+		}
+
+		// Search canary by dimension
+		canary, ok := cw.backend.Canaries[aws.StringValue(query.MetricStat.Metric.Dimensions[0].Value)]
+		if !ok {
+			output.MetricDataResults = append(output.MetricDataResults, result)
+			continue
+		}
+
+		// Runs are sorted newest first
+		period := time.Duration(*query.MetricStat.Period) * time.Second
+		for i := len(canary.Runs) - 1; i >= 0; i-- {
+			run := canary.Runs[i]
+			started := *run.Timeline.Started
+			if started.Before(*input.StartTime) || started.After(*input.EndTime) {
+				continue
+			}
+
+			passed := *run.Status.State == synthetics.CanaryRunStatePassed
+			value := 0.0
+			switch *query.MetricStat.Metric.MetricName {
+			case "SuccessPercent":
+				if passed {
+					value = 100
+				}
+			case "Duration":
+				value = 1000
+			case "Failed":
+				if passed == false {
+					value = 1
+				}
+			}
+			result.Timestamps = append(result.Timestamps, aws.Time(started.Truncate(period)))
+			result.Values = append(result.Values, aws.Float64(value))
+		}
+		output.MetricDataResults = append(output.MetricDataResults, result)
+	}
+	return output, nil
+}
+
+// PutMetricAlarmWithContext create or replace an alarm
+func (cw *CloudWatch) PutMetricAlarmWithContext(ctx aws.Context, input *cloudwatch.PutMetricAlarmInput, opts ...request.Option) (*cloudwatch.PutMetricAlarmOutput, error) {
+	cw.backend.mutex.Lock()
+	defer cw.backend.mutex.Unlock()
+
+	// Tags are applied only on alarm creation
+	if _, ok := cw.backend.Alarms[*input.AlarmName]; !ok {
+		tags := map[string]string{}
+		for _, tag := range input.Tags {
+			tags[*tag.Key] = *tag.Value
+		}
+		cw.backend.AlarmTags[*input.AlarmName] = tags
+	}
+
+	cw.backend.Alarms[*input.AlarmName] = &cloudwatch.MetricAlarm{
+		AlarmName:          input.AlarmName,
+		AlarmArn:           aws.String(fmt.Sprintf("arn:aws:cloudwatch:%s:%s:alarm:%s", cw.backend.Region, cw.backend.AccountID, *input.AlarmName)),
+		AlarmDescription:   input.AlarmDescription,
+		Namespace:          input.Namespace,
+		MetricName:         input.MetricName,
+		Dimensions:         input.Dimensions,
+		Statistic:          input.Statistic,
+		ComparisonOperator: input.ComparisonOperator,
+		Threshold:          input.Threshold,
+		Period:             input.Period,
+		EvaluationPeriods:  input.EvaluationPeriods,
+		AlarmActions:       input.AlarmActions,
+		OKActions:          input.OKActions,
+		StateValue:         aws.String(cloudwatch.StateValueInsufficientData),
+	}
+	return &cloudwatch.PutMetricAlarmOutput{}, nil
+}
+
+// DescribeAlarmsWithContext return alarms by name prefix
+func (cw *CloudWatch) DescribeAlarmsWithContext(ctx aws.Context, input *cloudwatch.DescribeAlarmsInput, opts ...request.Option) (*cloudwatch.DescribeAlarmsOutput, error) {
+	cw.backend.mutex.Lock()
+	defer cw.backend.mutex.Unlock()
+
+	output := &cloudwatch.DescribeAlarmsOutput{}
+	for _, name := range sortedKeys(cw.backend.Alarms) {
+		if strings.HasPrefix(name, aws.StringValue(input.AlarmNamePrefix)) == false {
+			continue
+		}
+		if len(input.AlarmNames) > 0 && contains(aws.StringValueSlice(input.AlarmNames), name) == false {
+			continue
+		}
+		output.MetricAlarms = append(output.MetricAlarms, cw.backend.Alarms[name])
+	}
+	return output, nil
+}
+
+// TagResourceWithContext add or overwrite alarm tags
+func (cw *CloudWatch) TagResourceWithContext(ctx aws.Context, input *cloudwatch.TagResourceInput, opts ...request.Option) (*cloudwatch.TagResourceOutput, error) {
+	cw.backend.mutex.Lock()
+	defer cw.backend.mutex.Unlock()
+
+	name := (*input.ResourceARN)[strings.LastIndex(*input.ResourceARN, ":alarm:")+len(":alarm:"):]
+	tags, ok := cw.backend.AlarmTags[name]
+	if !ok {
+		return &cloudwatch.TagResourceOutput{}, newError(cloudwatch.ErrCodeResourceNotFoundException, "Alarm %s not found", name)
+	}
+	for _, tag := range input.Tags {
+		tags[*tag.Key] = *tag.Value
+	}
+	return &cloudwatch.TagResourceOutput{}, nil
+}
+
+// DeleteAlarmsWithContext delete alarms by name
+func (cw *CloudWatch) DeleteAlarmsWithContext(ctx aws.Context, input *cloudwatch.DeleteAlarmsInput, opts ...request.Option) (*cloudwatch.DeleteAlarmsOutput, error) {
+	cw.backend.mutex.Lock()
+	defer cw.backend.mutex.Unlock()
+
+	for _, name := range input.AlarmNames {
+		delete(cw.backend.Alarms, *name)
+		delete(cw.backend.AlarmTags, *name)
+	}
+	return &cloudwatch.DeleteAlarmsOutput{}, nil
+}
+
+func sortedKeys(alarms map[string]*cloudwatch.MetricAlarm) []string {
+	keys := []string{}
+	for key := range alarms {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// PutDashboardWithContext create or replace a dashboard
+func (cw *CloudWatch) PutDashboardWithContext(ctx aws.Context, input *cloudwatch.PutDashboardInput, opts ...request.Option) (*cloudwatch.PutDashboardOutput, error) {
+	cw.backend.mutex.Lock()
+	defer cw.backend.mutex.Unlock()
+
+	cw.backend.Dashboards[*input.DashboardName] = *input.DashboardBody
+	return &cloudwatch.PutDashboardOutput{}, nil
+}
+
+// GetDashboardWithContext return a dashboard body
+func (cw *CloudWatch) GetDashboardWithContext(ctx aws.Context, input *cloudwatch.GetDashboardInput, opts ...request.Option) (*cloudwatch.GetDashboardOutput, error) {
+	cw.backend.mutex.Lock()
+	defer cw.backend.mutex.Unlock()
+
+	body, ok := cw.backend.Dashboards[*input.DashboardName]
+	if !ok {
+		return &cloudwatch.GetDashboardOutput{}, newError(cloudwatch.ErrCodeResourceNotFound, "Dashboard %s does not exist", *input.DashboardName)
+	}
+	return &cloudwatch.GetDashboardOutput{
+		DashboardName: input.DashboardName,
+		DashboardBody: aws.String(body),
+	}, nil
+}
+
+// DeleteDashboardsWithContext delete dashboards by name
+func (cw *CloudWatch) DeleteDashboardsWithContext(ctx aws.Context, input *cloudwatch.DeleteDashboardsInput, opts ...request.Option) (*cloudwatch.DeleteDashboardsOutput, error) {
+	cw.backend.mutex.Lock()
+	defer cw.backend.mutex.Unlock()
+
+	for _, name := range input.DashboardNames {
+		if _, ok := cw.backend.Dashboards[*name]; !ok {
+			return &cloudwatch.DeleteDashboardsOutput{}, newError(cloudwatch.ErrCodeDashboardNotFoundError, "Dashboard %s does not exist", *name)
+		}
+		delete(cw.backend.Dashboards, *name)
+	}
+	return &cloudwatch.DeleteDashboardsOutput{}, nil
+}
@@ -0,0 +1,110 @@
+package fake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+)
+
+// Backend is an in-memory AWS backend, it models only the behaviours used by the CLI
+type Backend struct {
+	mutex sync.Mutex
+
+	AccountID string
+	Region    string
+
+	Canaries map[string]*Canary
+	Buckets  map[string]map[string][]byte
+	Roles    map[string]*Role
+	Layers   map[string][]int64
+	Lambdas  map[string]bool
+
+	// BucketTags contains S3 buckets tags by bucket name
+	BucketTags map[string]map[string]string
+
+	// Groups contains Synthetics groups by name
+	Groups map[string]*Group
+
+	// Alarms contains CloudWatch alarms by name
+	Alarms map[string]*cloudwatch.MetricAlarm
+
+	// AlarmTags contains CloudWatch alarms tags by alarm name
+	AlarmTags map[string]map[string]string
+
+	// Dashboards contains CloudWatch dashboards body by name
+	Dashboards map[string]string
+
+	// LogEvents contains log events by log group name
+	LogEvents map[string][]*cloudwatchlogs.FilteredLogEvent
+
+	// Topics contains SNS topics by ARN
+	Topics map[string]*Topic
+
+	// Runtimes contains available Synthetics runtimes
+	Runtimes []*synthetics.RuntimeVersion
+
+	// FailingRuns contains the failure reason of canaries whose runs must fail
+	FailingRuns map[string]string
+
+	// ClockSkew is added to local time for runs timeline, simulating a service clock different from the local one
+	ClockSkew time.Duration
+}
+
+// NewBackend creates a new empty in-memory backend
+func NewBackend() *Backend {
+	return &Backend{
+		AccountID: "123456789012",
+		Region:    "us-east-1",
+
+		Canaries:    map[string]*Canary{},
+		Buckets:     map[string]map[string][]byte{},
+		BucketTags:  map[string]map[string]string{},
+		Roles:       map[string]*Role{},
+		Layers:      map[string][]int64{},
+		Lambdas:     map[string]bool{},
+		Groups:      map[string]*Group{},
+		Alarms:      map[string]*cloudwatch.MetricAlarm{},
+		AlarmTags:   map[string]map[string]string{},
+		Dashboards:  map[string]string{},
+		LogEvents:   map[string][]*cloudwatchlogs.FilteredLogEvent{},
+		Topics:      map[string]*Topic{},
+		FailingRuns: map[string]string{},
+		Runtimes: []*synthetics.RuntimeVersion{
+			newRuntime("syn-nodejs-puppeteer-3.8", "2022-07-01", "2024-03-08"),
+			newRuntime("syn-nodejs-puppeteer-3.9", "2023-01-20", "2025-01-08"),
+			newRuntime("syn-nodejs-puppeteer-6.2", "2024-02-02", ""),
+			newRuntime("syn-nodejs-puppeteer-7.0", "2024-07-11", ""),
+			newRuntime("syn-python-selenium-1.3", "2022-02-03", "2024-03-08"),
+			newRuntime("syn-python-selenium-3.0", "2024-03-14", ""),
+		},
+	}
+}
+
+// NewClients return AWS clients backed by the provided in-memory backend
+func NewClients(backend *Backend) *awsinternal.Clients {
+	return &awsinternal.Clients{
+		Region: aws.String(backend.Region),
+
+		Synthetics:       &Synthetics{backend: backend},
+		SyntheticsDryRun: &SyntheticsDryRun{backend: backend},
+		S3:               &S3{backend: backend},
+		S3Uploader:       &S3Uploader{backend: backend},
+		Lambda:           &Lambda{backend: backend},
+		IAM:              &IAM{backend: backend},
+		STS:              &STS{backend: backend},
+		CloudWatch:       &CloudWatch{backend: backend},
+		CloudWatchLogs:   &CloudWatchLogs{backend: backend},
+		SNS:              &SNS{backend: backend},
+	}
+}
+
+func newError(code string, format string, a ...interface{}) error {
+	return awserr.New(code, fmt.Sprintf(format, a...), nil)
+}
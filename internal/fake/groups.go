@@ -0,0 +1,153 @@
+package fake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+)
+
+// Group is an in-memory Synthetics group
+type Group struct {
+	Group     *synthetics.Group
+	Resources []string
+}
+
+func (s *Synthetics) getGroup(identifier *string) (*Group, error) {
+	for _, group := range s.backend.Groups {
+		if *group.Group.Name == *identifier || *group.Group.Arn == *identifier || *group.Group.Id == *identifier {
+			return group, nil
+		}
+	}
+	return nil, newError(synthetics.ErrCodeResourceNotFoundException, "Group %s not found", *identifier)
+}
+
+// CreateGroupWithContext create a new group
+func (s *Synthetics) CreateGroupWithContext(ctx aws.Context, input *synthetics.CreateGroupInput, opts ...request.Option) (*synthetics.CreateGroupOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	if _, ok := s.backend.Groups[*input.Name]; ok {
+		return &synthetics.CreateGroupOutput{}, newError(synthetics.ErrCodeConflictException, "Group %s already exists", *input.Name)
+	}
+
+	id := fmt.Sprintf("group%04d", len(s.backend.Groups)+1)
+	group := &Group{
+		Group: &synthetics.Group{
+			Id:   aws.String(id),
+			Name: aws.String(*input.Name),
+			Arn:  aws.String(fmt.Sprintf("arn:aws:synthetics:%s:%s:group:%s", s.backend.Region, s.backend.AccountID, id)),
+			Tags: input.Tags,
+		},
+		Resources: []string{},
+	}
+	s.backend.Groups[*input.Name] = group
+
+	return &synthetics.CreateGroupOutput{Group: group.Group}, nil
+}
+
+// GetGroupWithContext return a group by name, id or ARN
+func (s *Synthetics) GetGroupWithContext(ctx aws.Context, input *synthetics.GetGroupInput, opts ...request.Option) (*synthetics.GetGroupOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	group, err := s.getGroup(input.GroupIdentifier)
+	if err != nil {
+		return &synthetics.GetGroupOutput{}, err
+	}
+	return &synthetics.GetGroupOutput{Group: group.Group}, nil
+}
+
+// DeleteGroupWithContext delete a group
+func (s *Synthetics) DeleteGroupWithContext(ctx aws.Context, input *synthetics.DeleteGroupInput, opts ...request.Option) (*synthetics.DeleteGroupOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	group, err := s.getGroup(input.GroupIdentifier)
+	if err != nil {
+		return &synthetics.DeleteGroupOutput{}, err
+	}
+	delete(s.backend.Groups, *group.Group.Name)
+	return &synthetics.DeleteGroupOutput{}, nil
+}
+
+// ListGroupsWithContext return all groups in a single page
+func (s *Synthetics) ListGroupsWithContext(ctx aws.Context, input *synthetics.ListGroupsInput, opts ...request.Option) (*synthetics.ListGroupsOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	output := &synthetics.ListGroupsOutput{}
+	for _, group := range s.backend.Groups {
+		output.Groups = append(output.Groups, &synthetics.GroupSummary{
+			Arn:  group.Group.Arn,
+			Id:   group.Group.Id,
+			Name: group.Group.Name,
+		})
+	}
+	return output, nil
+}
+
+// ListGroupResourcesWithContext return group resources ARNs
+func (s *Synthetics) ListGroupResourcesWithContext(ctx aws.Context, input *synthetics.ListGroupResourcesInput, opts ...request.Option) (*synthetics.ListGroupResourcesOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	group, err := s.getGroup(input.GroupIdentifier)
+	if err != nil {
+		return &synthetics.ListGroupResourcesOutput{}, err
+	}
+	return &synthetics.ListGroupResourcesOutput{Resources: aws.StringSlice(group.Resources)}, nil
+}
+
+// ListAssociatedGroupsWithContext return groups the resource is associated with
+func (s *Synthetics) ListAssociatedGroupsWithContext(ctx aws.Context, input *synthetics.ListAssociatedGroupsInput, opts ...request.Option) (*synthetics.ListAssociatedGroupsOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	output := &synthetics.ListAssociatedGroupsOutput{}
+	for _, group := range s.backend.Groups {
+		if contains(group.Resources, *input.ResourceArn) {
+			output.Groups = append(output.Groups, &synthetics.GroupSummary{
+				Arn:  group.Group.Arn,
+				Id:   group.Group.Id,
+				Name: group.Group.Name,
+			})
+		}
+	}
+	return output, nil
+}
+
+// AssociateResourceWithContext add a resource to a group
+func (s *Synthetics) AssociateResourceWithContext(ctx aws.Context, input *synthetics.AssociateResourceInput, opts ...request.Option) (*synthetics.AssociateResourceOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	group, err := s.getGroup(input.GroupIdentifier)
+	if err != nil {
+		return &synthetics.AssociateResourceOutput{}, err
+	}
+	if contains(group.Resources, *input.ResourceArn) {
+		return &synthetics.AssociateResourceOutput{}, newError(synthetics.ErrCodeConflictException, "Resource already associated")
+	}
+	group.Resources = append(group.Resources, *input.ResourceArn)
+	return &synthetics.AssociateResourceOutput{}, nil
+}
+
+// DisassociateResourceWithContext remove a resource from a group
+func (s *Synthetics) DisassociateResourceWithContext(ctx aws.Context, input *synthetics.DisassociateResourceInput, opts ...request.Option) (*synthetics.DisassociateResourceOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	group, err := s.getGroup(input.GroupIdentifier)
+	if err != nil {
+		return &synthetics.DisassociateResourceOutput{}, err
+	}
+	for i, resource := range group.Resources {
+		if resource == *input.ResourceArn {
+			group.Resources = append(group.Resources[:i], group.Resources[i+1:]...)
+			return &synthetics.DisassociateResourceOutput{}, nil
+		}
+	}
+	return &synthetics.DisassociateResourceOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Resource not associated")
+}
@@ -0,0 +1,195 @@
+package fake
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+)
+
+// Role is the in-memory IAM role state
+type Role struct {
+	Role     *iam.Role
+	Policies map[string]string
+}
+
+// IAM is an in-memory implementation of the IAM API
+type IAM struct {
+	iamiface.IAMAPI
+	backend *Backend
+}
+
+// CreateRoleWithContext creates a role
+func (i *IAM) CreateRoleWithContext(ctx aws.Context, input *iam.CreateRoleInput, opts ...request.Option) (*iam.CreateRoleOutput, error) {
+	i.backend.mutex.Lock()
+	defer i.backend.mutex.Unlock()
+
+	if _, ok := i.backend.Roles[*input.RoleName]; ok {
+		return &iam.CreateRoleOutput{}, newError(iam.ErrCodeEntityAlreadyExistsException, "Role %s already exists", *input.RoleName)
+	}
+
+	role := &Role{
+		Role: &iam.Role{
+			RoleName:                 input.RoleName,
+			Arn:                      aws.String(fmt.Sprintf("arn:aws:iam::%s:role/%s", i.backend.AccountID, *input.RoleName)),
+			AssumeRolePolicyDocument: input.AssumeRolePolicyDocument,
+			Tags:                     input.Tags,
+		},
+		Policies: map[string]string{},
+	}
+	i.backend.Roles[*input.RoleName] = role
+
+	return &iam.CreateRoleOutput{Role: role.Role}, nil
+}
+
+// GetRoleWithContext return a role
+func (i *IAM) GetRoleWithContext(ctx aws.Context, input *iam.GetRoleInput, opts ...request.Option) (*iam.GetRoleOutput, error) {
+	i.backend.mutex.Lock()
+	defer i.backend.mutex.Unlock()
+
+	role, ok := i.backend.Roles[*input.RoleName]
+	if !ok {
+		return &iam.GetRoleOutput{}, newError(iam.ErrCodeNoSuchEntityException, "Role %s not found", *input.RoleName)
+	}
+
+	return &iam.GetRoleOutput{Role: role.Role}, nil
+}
+
+// WaitUntilRoleExistsWithContext return immediately if role exists
+func (i *IAM) WaitUntilRoleExistsWithContext(ctx aws.Context, input *iam.GetRoleInput, opts ...request.WaiterOption) error {
+	_, err := i.GetRoleWithContext(ctx, input)
+	return err
+}
+
+// DeleteRoleWithContext delete a role without inline policies
+func (i *IAM) DeleteRoleWithContext(ctx aws.Context, input *iam.DeleteRoleInput, opts ...request.Option) (*iam.DeleteRoleOutput, error) {
+	i.backend.mutex.Lock()
+	defer i.backend.mutex.Unlock()
+
+	role, ok := i.backend.Roles[*input.RoleName]
+	if !ok {
+		return &iam.DeleteRoleOutput{}, newError(iam.ErrCodeNoSuchEntityException, "Role %s not found", *input.RoleName)
+	}
+	if len(role.Policies) > 0 {
+		return &iam.DeleteRoleOutput{}, newError(iam.ErrCodeDeleteConflictException, "Role %s has inline policies", *input.RoleName)
+	}
+
+	delete(i.backend.Roles, *input.RoleName)
+	return &iam.DeleteRoleOutput{}, nil
+}
+
+// PutRolePolicyWithContext set a role inline policy
+func (i *IAM) PutRolePolicyWithContext(ctx aws.Context, input *iam.PutRolePolicyInput, opts ...request.Option) (*iam.PutRolePolicyOutput, error) {
+	i.backend.mutex.Lock()
+	defer i.backend.mutex.Unlock()
+
+	role, ok := i.backend.Roles[*input.RoleName]
+	if !ok {
+		return &iam.PutRolePolicyOutput{}, newError(iam.ErrCodeNoSuchEntityException, "Role %s not found", *input.RoleName)
+	}
+
+	role.Policies[*input.PolicyName] = *input.PolicyDocument
+	return &iam.PutRolePolicyOutput{}, nil
+}
+
+// GetRolePolicyWithContext return a role inline policy
+func (i *IAM) GetRolePolicyWithContext(ctx aws.Context, input *iam.GetRolePolicyInput, opts ...request.Option) (*iam.GetRolePolicyOutput, error) {
+	i.backend.mutex.Lock()
+	defer i.backend.mutex.Unlock()
+
+	role, ok := i.backend.Roles[*input.RoleName]
+	if !ok {
+		return &iam.GetRolePolicyOutput{}, newError(iam.ErrCodeNoSuchEntityException, "Role %s not found", *input.RoleName)
+	}
+	document, ok := role.Policies[*input.PolicyName]
+	if !ok {
+		return &iam.GetRolePolicyOutput{}, newError(iam.ErrCodeNoSuchEntityException, "Policy %s not found", *input.PolicyName)
+	}
+
+	return &iam.GetRolePolicyOutput{
+		RoleName:       input.RoleName,
+		PolicyName:     input.PolicyName,
+		PolicyDocument: aws.String(document),
+	}, nil
+}
+
+// DeleteRolePolicyWithContext delete a role inline policy
+func (i *IAM) DeleteRolePolicyWithContext(ctx aws.Context, input *iam.DeleteRolePolicyInput, opts ...request.Option) (*iam.DeleteRolePolicyOutput, error) {
+	i.backend.mutex.Lock()
+	defer i.backend.mutex.Unlock()
+
+	role, ok := i.backend.Roles[*input.RoleName]
+	if !ok {
+		return &iam.DeleteRolePolicyOutput{}, newError(iam.ErrCodeNoSuchEntityException, "Role %s not found", *input.RoleName)
+	}
+	if _, ok := role.Policies[*input.PolicyName]; !ok {
+		return &iam.DeleteRolePolicyOutput{}, newError(iam.ErrCodeNoSuchEntityException, "Policy %s not found", *input.PolicyName)
+	}
+
+	delete(role.Policies, *input.PolicyName)
+	return &iam.DeleteRolePolicyOutput{}, nil
+}
+
+// ListRolesWithContext return all roles, sorted by name
+func (i *IAM) ListRolesWithContext(ctx aws.Context, input *iam.ListRolesInput, opts ...request.Option) (*iam.ListRolesOutput, error) {
+	i.backend.mutex.Lock()
+	defer i.backend.mutex.Unlock()
+
+	names := []string{}
+	for name := range i.backend.Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	output := &iam.ListRolesOutput{
+		IsTruncated: aws.Bool(false),
+	}
+	for _, name := range names {
+		output.Roles = append(output.Roles, i.backend.Roles[name].Role)
+	}
+	return output, nil
+}
+
+// TagRoleWithContext add or replace role tags
+func (i *IAM) TagRoleWithContext(ctx aws.Context, input *iam.TagRoleInput, opts ...request.Option) (*iam.TagRoleOutput, error) {
+	i.backend.mutex.Lock()
+	defer i.backend.mutex.Unlock()
+
+	role, ok := i.backend.Roles[*input.RoleName]
+	if !ok {
+		return &iam.TagRoleOutput{}, newError(iam.ErrCodeNoSuchEntityException, "Role %s not found", *input.RoleName)
+	}
+
+	for _, tag := range input.Tags {
+		replaced := false
+		for _, current := range role.Role.Tags {
+			if *current.Key == *tag.Key {
+				current.Value = tag.Value
+				replaced = true
+			}
+		}
+		if !replaced {
+			role.Role.Tags = append(role.Role.Tags, &iam.Tag{Key: tag.Key, Value: tag.Value})
+		}
+	}
+	return &iam.TagRoleOutput{}, nil
+}
+
+// ListRoleTagsWithContext return role tags
+func (i *IAM) ListRoleTagsWithContext(ctx aws.Context, input *iam.ListRoleTagsInput, opts ...request.Option) (*iam.ListRoleTagsOutput, error) {
+	i.backend.mutex.Lock()
+	defer i.backend.mutex.Unlock()
+
+	role, ok := i.backend.Roles[*input.RoleName]
+	if !ok {
+		return &iam.ListRoleTagsOutput{}, newError(iam.ErrCodeNoSuchEntityException, "Role %s not found", *input.RoleName)
+	}
+
+	return &iam.ListRoleTagsOutput{
+		Tags:        role.Role.Tags,
+		IsTruncated: aws.Bool(false),
+	}, nil
+}
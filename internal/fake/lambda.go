@@ -0,0 +1,112 @@
+package fake
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+)
+
+// Lambda is an in-memory implementation of the Lambda API
+type Lambda struct {
+	lambdaiface.LambdaAPI
+	backend *Backend
+}
+
+// ListLayerVersionsWithContext return layer versions
+func (l *Lambda) ListLayerVersionsWithContext(ctx aws.Context, input *lambda.ListLayerVersionsInput, opts ...request.Option) (*lambda.ListLayerVersionsOutput, error) {
+	l.backend.mutex.Lock()
+	defer l.backend.mutex.Unlock()
+
+	output := &lambda.ListLayerVersionsOutput{}
+	for _, version := range l.backend.Layers[*input.LayerName] {
+		output.LayerVersions = append(output.LayerVersions, &lambda.LayerVersionsListItem{
+			Version: aws.Int64(version),
+		})
+	}
+	return output, nil
+}
+
+// DeleteLayerVersionWithContext delete a layer version
+func (l *Lambda) DeleteLayerVersionWithContext(ctx aws.Context, input *lambda.DeleteLayerVersionInput, opts ...request.Option) (*lambda.DeleteLayerVersionOutput, error) {
+	l.backend.mutex.Lock()
+	defer l.backend.mutex.Unlock()
+
+	versions := []int64{}
+	for _, version := range l.backend.Layers[*input.LayerName] {
+		if version != *input.VersionNumber {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		delete(l.backend.Layers, *input.LayerName)
+	} else {
+		l.backend.Layers[*input.LayerName] = versions
+	}
+
+	return &lambda.DeleteLayerVersionOutput{}, nil
+}
+
+// DeleteFunctionWithContext delete a function
+func (l *Lambda) DeleteFunctionWithContext(ctx aws.Context, input *lambda.DeleteFunctionInput, opts ...request.Option) (*lambda.DeleteFunctionOutput, error) {
+	l.backend.mutex.Lock()
+	defer l.backend.mutex.Unlock()
+
+	if _, ok := l.backend.Lambdas[*input.FunctionName]; !ok {
+		return &lambda.DeleteFunctionOutput{}, newError(lambda.ErrCodeResourceNotFoundException, "Function %s not found", *input.FunctionName)
+	}
+
+	delete(l.backend.Lambdas, *input.FunctionName)
+	return &lambda.DeleteFunctionOutput{}, nil
+}
+
+// GetFunctionConfigurationWithContext return canary function environment variables
+func (l *Lambda) GetFunctionConfigurationWithContext(ctx aws.Context, input *lambda.GetFunctionConfigurationInput, opts ...request.Option) (*lambda.FunctionConfiguration, error) {
+	l.backend.mutex.Lock()
+	defer l.backend.mutex.Unlock()
+
+	for _, canary := range l.backend.Canaries {
+		if aws.StringValue(canary.Canary.EngineArn) == *input.FunctionName {
+			return &lambda.FunctionConfiguration{
+				FunctionArn: canary.Canary.EngineArn,
+				Environment: &lambda.EnvironmentResponse{
+					Variables: canary.EnvironmentVariables,
+				},
+			}, nil
+		}
+	}
+
+	return &lambda.FunctionConfiguration{}, newError(lambda.ErrCodeResourceNotFoundException, "Function %s not found", *input.FunctionName)
+}
+
+// GetLayerVersionByArnWithContext return canary code layer, content hash is elaborated from last deployed code
+func (l *Lambda) GetLayerVersionByArnWithContext(ctx aws.Context, input *lambda.GetLayerVersionByArnInput, opts ...request.Option) (*lambda.GetLayerVersionByArnOutput, error) {
+	l.backend.mutex.Lock()
+	defer l.backend.mutex.Unlock()
+
+	for _, canary := range l.backend.Canaries {
+		if *canary.Canary.Code.SourceLocationArn != *input.Arn {
+			continue
+		}
+
+		// Read code from source bucket when uploaded
+		content := canary.Code.ZipFile
+		if canary.Code.S3Bucket != nil {
+			content = l.backend.Buckets[*canary.Code.S3Bucket][*canary.Code.S3Key]
+		}
+		hash := sha256.Sum256(content)
+
+		return &lambda.GetLayerVersionByArnOutput{
+			LayerVersionArn: input.Arn,
+			Content: &lambda.LayerVersionContentOutput{
+				CodeSha256: aws.String(base64.StdEncoding.EncodeToString(hash[:])),
+				CodeSize:   aws.Int64(int64(len(content))),
+			},
+		}, nil
+	}
+
+	return &lambda.GetLayerVersionByArnOutput{}, newError(lambda.ErrCodeResourceNotFoundException, "Layer version %s not found", *input.Arn)
+}
@@ -0,0 +1,51 @@
+package fake
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+)
+
+// CloudWatchLogs is an in-memory implementation of the CloudWatch Logs API
+type CloudWatchLogs struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+	backend *Backend
+}
+
+func (b *Backend) addLogEvent(logGroupName string, message string) {
+	events := b.LogEvents[logGroupName]
+	b.LogEvents[logGroupName] = append(events, &cloudwatchlogs.FilteredLogEvent{
+		EventId:       aws.String(fmt.Sprintf("%d", len(events)+1)),
+		LogStreamName: aws.String("fake"),
+		Message:       aws.String(message),
+		Timestamp:     aws.Int64(time.Now().UnixNano() / int64(time.Millisecond)),
+	})
+}
+
+// FilterLogEventsWithContext return log group events, the filter pattern is matched as a plain substring
+func (l *CloudWatchLogs) FilterLogEventsWithContext(ctx aws.Context, input *cloudwatchlogs.FilterLogEventsInput, opts ...request.Option) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	l.backend.mutex.Lock()
+	defer l.backend.mutex.Unlock()
+
+	events, ok := l.backend.LogEvents[*input.LogGroupName]
+	if !ok {
+		return &cloudwatchlogs.FilterLogEventsOutput{}, newError(cloudwatchlogs.ErrCodeResourceNotFoundException, "Log group %s not found", *input.LogGroupName)
+	}
+
+	output := &cloudwatchlogs.FilterLogEventsOutput{}
+	for _, event := range events {
+		if input.StartTime != nil && *event.Timestamp < *input.StartTime {
+			continue
+		}
+		if input.FilterPattern != nil && strings.Contains(*event.Message, *input.FilterPattern) == false {
+			continue
+		}
+		output.Events = append(output.Events, event)
+	}
+	return output, nil
+}
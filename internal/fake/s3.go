@@ -0,0 +1,235 @@
+package fake
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// S3 is an in-memory implementation of the S3 API
+type S3 struct {
+	s3iface.S3API
+	backend *Backend
+}
+
+// S3Uploader is an in-memory implementation of the S3 upload manager
+type S3Uploader struct {
+	backend *Backend
+}
+
+func (b *Backend) listKeys(bucket string, prefix string) []string {
+	keys := []string{}
+	for key := range b.Buckets[bucket] {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CreateBucketWithContext creates an empty bucket
+func (s *S3) CreateBucketWithContext(ctx aws.Context, input *s3.CreateBucketInput, opts ...request.Option) (*s3.CreateBucketOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	if _, ok := s.backend.Buckets[*input.Bucket]; ok {
+		return &s3.CreateBucketOutput{}, newError(s3.ErrCodeBucketAlreadyOwnedByYou, "Bucket %s already exists", *input.Bucket)
+	}
+
+	s.backend.Buckets[*input.Bucket] = map[string][]byte{}
+	return &s3.CreateBucketOutput{}, nil
+}
+
+// DeleteBucketWithContext delete an empty bucket
+func (s *S3) DeleteBucketWithContext(ctx aws.Context, input *s3.DeleteBucketInput, opts ...request.Option) (*s3.DeleteBucketOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	objects, ok := s.backend.Buckets[*input.Bucket]
+	if !ok {
+		return &s3.DeleteBucketOutput{}, newError(s3.ErrCodeNoSuchBucket, "Bucket %s not found", *input.Bucket)
+	}
+	if len(objects) > 0 {
+		return &s3.DeleteBucketOutput{}, newError("BucketNotEmpty", "Bucket %s is not empty", *input.Bucket)
+	}
+
+	delete(s.backend.Buckets, *input.Bucket)
+	delete(s.backend.BucketTags, *input.Bucket)
+	return &s3.DeleteBucketOutput{}, nil
+}
+
+// PutBucketTaggingWithContext replace bucket tags
+func (s *S3) PutBucketTaggingWithContext(ctx aws.Context, input *s3.PutBucketTaggingInput, opts ...request.Option) (*s3.PutBucketTaggingOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	if _, ok := s.backend.Buckets[*input.Bucket]; !ok {
+		return &s3.PutBucketTaggingOutput{}, newError(s3.ErrCodeNoSuchBucket, "Bucket %s not found", *input.Bucket)
+	}
+
+	tags := map[string]string{}
+	for _, tag := range input.Tagging.TagSet {
+		tags[*tag.Key] = *tag.Value
+	}
+	s.backend.BucketTags[*input.Bucket] = tags
+	return &s3.PutBucketTaggingOutput{}, nil
+}
+
+// GetBucketTaggingWithContext return bucket tags
+func (s *S3) GetBucketTaggingWithContext(ctx aws.Context, input *s3.GetBucketTaggingInput, opts ...request.Option) (*s3.GetBucketTaggingOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	if _, ok := s.backend.Buckets[*input.Bucket]; !ok {
+		return &s3.GetBucketTaggingOutput{}, newError(s3.ErrCodeNoSuchBucket, "Bucket %s not found", *input.Bucket)
+	}
+	if len(s.backend.BucketTags[*input.Bucket]) == 0 {
+		return &s3.GetBucketTaggingOutput{}, newError("NoSuchTagSet", "The TagSet does not exist")
+	}
+
+	output := &s3.GetBucketTaggingOutput{}
+	for key, value := range s.backend.BucketTags[*input.Bucket] {
+		output.TagSet = append(output.TagSet, &s3.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+	return output, nil
+}
+
+// PutPublicAccessBlockWithContext does nothing on an existing bucket
+func (s *S3) PutPublicAccessBlockWithContext(ctx aws.Context, input *s3.PutPublicAccessBlockInput, opts ...request.Option) (*s3.PutPublicAccessBlockOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	if _, ok := s.backend.Buckets[*input.Bucket]; !ok {
+		return &s3.PutPublicAccessBlockOutput{}, newError(s3.ErrCodeNoSuchBucket, "Bucket %s not found", *input.Bucket)
+	}
+	return &s3.PutPublicAccessBlockOutput{}, nil
+}
+
+// PutBucketLifecycleConfigurationWithContext does nothing on an existing bucket
+func (s *S3) PutBucketLifecycleConfigurationWithContext(ctx aws.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...request.Option) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	if _, ok := s.backend.Buckets[*input.Bucket]; !ok {
+		return &s3.PutBucketLifecycleConfigurationOutput{}, newError(s3.ErrCodeNoSuchBucket, "Bucket %s not found", *input.Bucket)
+	}
+	return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+}
+
+// ListObjectsWithContext list all bucket objects with prefix
+func (s *S3) ListObjectsWithContext(ctx aws.Context, input *s3.ListObjectsInput, opts ...request.Option) (*s3.ListObjectsOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	if _, ok := s.backend.Buckets[*input.Bucket]; !ok {
+		return &s3.ListObjectsOutput{}, newError(s3.ErrCodeNoSuchBucket, "Bucket %s not found", *input.Bucket)
+	}
+
+	output := &s3.ListObjectsOutput{
+		IsTruncated: aws.Bool(false),
+	}
+	for _, key := range s.backend.listKeys(*input.Bucket, aws.StringValue(input.Prefix)) {
+		output.Contents = append(output.Contents, &s3.Object{
+			Key:  aws.String(key),
+			Size: aws.Int64(int64(len(s.backend.Buckets[*input.Bucket][key]))),
+		})
+	}
+	return output, nil
+}
+
+// ListObjectsV2WithContext list all bucket objects with prefix
+func (s *S3) ListObjectsV2WithContext(ctx aws.Context, input *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	if _, ok := s.backend.Buckets[*input.Bucket]; !ok {
+		return &s3.ListObjectsV2Output{}, newError(s3.ErrCodeNoSuchBucket, "Bucket %s not found", *input.Bucket)
+	}
+
+	output := &s3.ListObjectsV2Output{
+		IsTruncated: aws.Bool(false),
+	}
+	for _, key := range s.backend.listKeys(*input.Bucket, aws.StringValue(input.Prefix)) {
+		output.Contents = append(output.Contents, &s3.Object{
+			Key:  aws.String(key),
+			Size: aws.Int64(int64(len(s.backend.Buckets[*input.Bucket][key]))),
+		})
+	}
+	output.KeyCount = aws.Int64(int64(len(output.Contents)))
+	return output, nil
+}
+
+// GetObjectWithContext return object content
+func (s *S3) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	objects, ok := s.backend.Buckets[*input.Bucket]
+	if !ok {
+		return &s3.GetObjectOutput{}, newError(s3.ErrCodeNoSuchBucket, "Bucket %s not found", *input.Bucket)
+	}
+	content, ok := objects[*input.Key]
+	if !ok {
+		return &s3.GetObjectOutput{}, newError(s3.ErrCodeNoSuchKey, "Object %s not found", *input.Key)
+	}
+
+	return &s3.GetObjectOutput{
+		Body:          ioutil.NopCloser(bytes.NewReader(content)),
+		ContentLength: aws.Int64(int64(len(content))),
+	}, nil
+}
+
+// DeleteObjectsWithContext delete objects
+func (s *S3) DeleteObjectsWithContext(ctx aws.Context, input *s3.DeleteObjectsInput, opts ...request.Option) (*s3.DeleteObjectsOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	objects, ok := s.backend.Buckets[*input.Bucket]
+	if !ok {
+		return &s3.DeleteObjectsOutput{}, newError(s3.ErrCodeNoSuchBucket, "Bucket %s not found", *input.Bucket)
+	}
+
+	output := &s3.DeleteObjectsOutput{}
+	for _, object := range input.Delete.Objects {
+		delete(objects, *object.Key)
+		output.Deleted = append(output.Deleted, &s3.DeletedObject{Key: object.Key})
+	}
+	return output, nil
+}
+
+// Upload store the object content
+func (u *S3Uploader) Upload(input *s3manager.UploadInput, opts ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	return u.UploadWithContext(aws.BackgroundContext(), input, opts...)
+}
+
+// UploadWithContext store the object content
+func (u *S3Uploader) UploadWithContext(ctx aws.Context, input *s3manager.UploadInput, opts ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	content, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return &s3manager.UploadOutput{}, err
+	}
+
+	u.backend.mutex.Lock()
+	defer u.backend.mutex.Unlock()
+
+	objects, ok := u.backend.Buckets[*input.Bucket]
+	if !ok {
+		return &s3manager.UploadOutput{}, newError(s3.ErrCodeNoSuchBucket, "Bucket %s not found", *input.Bucket)
+	}
+	objects[*input.Key] = content
+
+	return &s3manager.UploadOutput{
+		Location: "s3://" + *input.Bucket + "/" + *input.Key,
+	}, nil
+}
@@ -0,0 +1,155 @@
+package fake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+// Topic is an in-memory SNS topic
+type Topic struct {
+	Attributes    map[string]string
+	Subscriptions []*sns.Subscription
+	Tags          map[string]string
+}
+
+// SNS is an in-memory implementation of the SNS API
+type SNS struct {
+	snsiface.SNSAPI
+	backend *Backend
+}
+
+func (s *SNS) getTopic(arn *string) (*Topic, error) {
+	topic, ok := s.backend.Topics[aws.StringValue(arn)]
+	if !ok {
+		return nil, newError(sns.ErrCodeNotFoundException, "Topic does not exist")
+	}
+	return topic, nil
+}
+
+// CreateTopicWithContext create a topic, an existing topic is returned as is
+func (s *SNS) CreateTopicWithContext(ctx aws.Context, input *sns.CreateTopicInput, opts ...request.Option) (*sns.CreateTopicOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	arn := fmt.Sprintf("arn:aws:sns:%s:%s:%s", s.backend.Region, s.backend.AccountID, *input.Name)
+	if _, ok := s.backend.Topics[arn]; !ok {
+		topic := &Topic{
+			Attributes: map[string]string{},
+			Tags:       map[string]string{},
+		}
+		for key, value := range input.Attributes {
+			topic.Attributes[key] = aws.StringValue(value)
+		}
+		for _, tag := range input.Tags {
+			topic.Tags[*tag.Key] = *tag.Value
+		}
+		s.backend.Topics[arn] = topic
+	}
+	return &sns.CreateTopicOutput{TopicArn: aws.String(arn)}, nil
+}
+
+// GetTopicAttributesWithContext return topic attributes
+func (s *SNS) GetTopicAttributesWithContext(ctx aws.Context, input *sns.GetTopicAttributesInput, opts ...request.Option) (*sns.GetTopicAttributesOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	topic, err := s.getTopic(input.TopicArn)
+	if err != nil {
+		return &sns.GetTopicAttributesOutput{}, err
+	}
+	attributes := map[string]*string{
+		"TopicArn": input.TopicArn,
+	}
+	for key, value := range topic.Attributes {
+		attributes[key] = aws.String(value)
+	}
+	return &sns.GetTopicAttributesOutput{Attributes: attributes}, nil
+}
+
+// SetTopicAttributesWithContext set a topic attribute
+func (s *SNS) SetTopicAttributesWithContext(ctx aws.Context, input *sns.SetTopicAttributesInput, opts ...request.Option) (*sns.SetTopicAttributesOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	topic, err := s.getTopic(input.TopicArn)
+	if err != nil {
+		return &sns.SetTopicAttributesOutput{}, err
+	}
+	topic.Attributes[*input.AttributeName] = aws.StringValue(input.AttributeValue)
+	return &sns.SetTopicAttributesOutput{}, nil
+}
+
+// TagResourceWithContext add or overwrite topic tags
+func (s *SNS) TagResourceWithContext(ctx aws.Context, input *sns.TagResourceInput, opts ...request.Option) (*sns.TagResourceOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	topic, err := s.getTopic(input.ResourceArn)
+	if err != nil {
+		return &sns.TagResourceOutput{}, err
+	}
+	for _, tag := range input.Tags {
+		topic.Tags[*tag.Key] = *tag.Value
+	}
+	return &sns.TagResourceOutput{}, nil
+}
+
+// DeleteTopicWithContext delete a topic, deleting a missing topic is not an error
+func (s *SNS) DeleteTopicWithContext(ctx aws.Context, input *sns.DeleteTopicInput, opts ...request.Option) (*sns.DeleteTopicOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	delete(s.backend.Topics, *input.TopicArn)
+	return &sns.DeleteTopicOutput{}, nil
+}
+
+// SubscribeWithContext add a confirmed subscription to a topic
+func (s *SNS) SubscribeWithContext(ctx aws.Context, input *sns.SubscribeInput, opts ...request.Option) (*sns.SubscribeOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	topic, err := s.getTopic(input.TopicArn)
+	if err != nil {
+		return &sns.SubscribeOutput{}, err
+	}
+	subscriptionArn := fmt.Sprintf("%s:%d", *input.TopicArn, len(topic.Subscriptions)+1)
+	topic.Subscriptions = append(topic.Subscriptions, &sns.Subscription{
+		TopicArn:        input.TopicArn,
+		Protocol:        input.Protocol,
+		Endpoint:        input.Endpoint,
+		SubscriptionArn: aws.String(subscriptionArn),
+	})
+	return &sns.SubscribeOutput{SubscriptionArn: aws.String(subscriptionArn)}, nil
+}
+
+// UnsubscribeWithContext remove a subscription
+func (s *SNS) UnsubscribeWithContext(ctx aws.Context, input *sns.UnsubscribeInput, opts ...request.Option) (*sns.UnsubscribeOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	for _, topic := range s.backend.Topics {
+		for i, subscription := range topic.Subscriptions {
+			if *subscription.SubscriptionArn == *input.SubscriptionArn {
+				topic.Subscriptions = append(topic.Subscriptions[:i], topic.Subscriptions[i+1:]...)
+				return &sns.UnsubscribeOutput{}, nil
+			}
+		}
+	}
+	return &sns.UnsubscribeOutput{}, newError(sns.ErrCodeNotFoundException, "Subscription does not exist")
+}
+
+// ListSubscriptionsByTopicWithContext return all topic subscriptions in a single page
+func (s *SNS) ListSubscriptionsByTopicWithContext(ctx aws.Context, input *sns.ListSubscriptionsByTopicInput, opts ...request.Option) (*sns.ListSubscriptionsByTopicOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	topic, err := s.getTopic(input.TopicArn)
+	if err != nil {
+		return &sns.ListSubscriptionsByTopicOutput{}, err
+	}
+	return &sns.ListSubscriptionsByTopicOutput{Subscriptions: topic.Subscriptions}, nil
+}
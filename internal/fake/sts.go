@@ -0,0 +1,30 @@
+package fake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+// STS is an in-memory implementation of the STS API
+type STS struct {
+	stsiface.STSAPI
+	backend *Backend
+}
+
+// GetCallerIdentity return the backend account
+func (s *STS) GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	return s.GetCallerIdentityWithContext(aws.BackgroundContext(), input)
+}
+
+// GetCallerIdentityWithContext return the backend account
+func (s *STS) GetCallerIdentityWithContext(ctx aws.Context, input *sts.GetCallerIdentityInput, opts ...request.Option) (*sts.GetCallerIdentityOutput, error) {
+	return &sts.GetCallerIdentityOutput{
+		Account: aws.String(s.backend.AccountID),
+		Arn:     aws.String(fmt.Sprintf("arn:aws:iam::%s:user/fake", s.backend.AccountID)),
+		UserId:  aws.String("FAKE"),
+	}, nil
+}
@@ -0,0 +1,580 @@
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	"github.com/aws/aws-sdk-go/service/synthetics/syntheticsiface"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+)
+
+// Canary is the in-memory canary state
+type Canary struct {
+	Canary *synthetics.Canary
+	Runs   []*synthetics.CanaryRun
+	Tags   map[string]*string
+
+	// Code is the last deployed code
+	Code *synthetics.CanaryCodeInput
+
+	// EnvironmentVariables are the last deployed environment variables, exposed by the canary Lambda function
+	EnvironmentVariables map[string]*string
+
+	// DryRuns contains the runs of started dry runs by dry run ID
+	DryRuns map[string][]*synthetics.CanaryRun
+}
+
+// Synthetics is an in-memory implementation of the Synthetics API
+type Synthetics struct {
+	syntheticsiface.SyntheticsAPI
+	backend *Backend
+}
+
+// advance move the canary to the next state, transitional states last a single status check
+func (b *Backend) advance(canary *Canary) {
+	status := canary.Canary.Status
+	switch *status.State {
+	case synthetics.CanaryStateCreating, synthetics.CanaryStateUpdating:
+		status.State = aws.String(synthetics.CanaryStateReady)
+	case synthetics.CanaryStateStarting:
+		// Scheduled canaries run as soon as they are started
+		if isManualSchedule(canary.Canary.Schedule) == false {
+			b.addRun(canary)
+		}
+		status.State = aws.String(synthetics.CanaryStateRunning)
+	case synthetics.CanaryStateRunning:
+		// Only manual canaries stops by themselves
+		if isManualSchedule(canary.Canary.Schedule) {
+			b.addRun(canary)
+			status.State = aws.String(synthetics.CanaryStateStopped)
+		}
+	case synthetics.CanaryStateStopping:
+		status.State = aws.String(synthetics.CanaryStateStopped)
+	}
+}
+
+func (b *Backend) addRun(canary *Canary) {
+	id := fmt.Sprintf("%08d-0000-4000-8000-000000000000", len(canary.Runs)+1)
+	now := time.Now().Add(b.ClockSkew)
+
+	// Elaborate run result
+	state := synthetics.CanaryRunStatePassed
+	reason := ""
+	if failure, ok := b.FailingRuns[*canary.Canary.Name]; ok {
+		state = synthetics.CanaryRunStateFailed
+		reason = failure
+	}
+
+	// Write run log into artifact bucket
+	location := strings.TrimPrefix(*canary.Canary.ArtifactS3Location, "s3://") + "/" + id
+	bucketName := strings.Split(location, "/")[0]
+	if objects, ok := b.Buckets[bucketName]; ok {
+		prefix := location[len(bucketName)+1:]
+		objects[prefix+"/log.txt"] = []byte(fmt.Sprintf("INFO: Canary %s run %s %s", *canary.Canary.Name, id, state))
+		objects[prefix+"/screenshots/01-run-succeeded.png"] = []byte("PNG")
+
+		// Compare screenshot with visual monitoring baseline
+		screenshot := `{"fileName":"01-run-succeeded.png"}`
+		if canary.Canary.VisualReference != nil {
+			result := "PASSED"
+			if state == synthetics.CanaryRunStateFailed {
+				result = "FAILED"
+			}
+			screenshot = fmt.Sprintf(`{"fileName":"01-run-succeeded.png","visualCompareResult":{"result":%q,"variance":12.5}}`, result)
+		}
+
+		// Steps are nested in customer script and use runtime statuses
+		stepStatus := "succeeded"
+		if state == synthetics.CanaryRunStateFailed {
+			stepStatus = "failed"
+		}
+		objects[prefix+"/SyntheticsReport-"+state+".json"] = []byte(fmt.Sprintf(
+			`{"canaryName":%q,"canaryRunId":%q,"status":%q,"startTime":%q,"endTime":%q,"customerScript":{"status":%q,"startTime":%q,"endTime":%q,"steps":[{"stepName":"run","status":%q,"failureReason":%q,"startTime":%q,"endTime":%q,"screenshots":[%s]}]}}`,
+			*canary.Canary.Name, id, state, now.Format(time.RFC3339), now.Format(time.RFC3339), state, now.Format(time.RFC3339), now.Format(time.RFC3339), stepStatus, reason, now.Format(time.RFC3339), now.Format(time.RFC3339), screenshot,
+		))
+	}
+
+	// Write run log into function log group
+	logGroupName := fmt.Sprintf("/aws/lambda/cwsyn-%s-%s", *canary.Canary.Name, *canary.Canary.Id)
+	b.addLogEvent(logGroupName, fmt.Sprintf("START RequestId: %s", id))
+	b.addLogEvent(logGroupName, fmt.Sprintf("INFO: Canary run %s %s", id, state))
+	b.addLogEvent(logGroupName, fmt.Sprintf("END RequestId: %s", id))
+
+	// Newest runs first
+	canary.Runs = append([]*synthetics.CanaryRun{
+		{
+			Id:                 aws.String(id),
+			Name:               canary.Canary.Name,
+			ArtifactS3Location: aws.String(location),
+			Status: &synthetics.CanaryRunStatus{
+				State:       aws.String(state),
+				StateReason: aws.String(reason),
+			},
+			Timeline: &synthetics.CanaryRunTimeline{
+				Started:   aws.Time(now),
+				Completed: aws.Time(now),
+			},
+		},
+	}, canary.Runs...)
+}
+
+func isManualSchedule(schedule *synthetics.CanaryScheduleOutput) bool {
+	return strings.HasPrefix(*schedule.Expression, "rate(0 ")
+}
+
+func canaryNameFromArn(arn *string) string {
+	parts := strings.Split(*arn, ":")
+	return parts[len(parts)-1]
+}
+
+func artifactConfigOutput(input *synthetics.ArtifactConfigInput_) *synthetics.ArtifactConfigOutput_ {
+	if input == nil {
+		return nil
+	}
+	return &synthetics.ArtifactConfigOutput_{
+		S3Encryption: input.S3Encryption,
+	}
+}
+
+func runConfigOutput(input *synthetics.CanaryRunConfigInput) *synthetics.CanaryRunConfigOutput {
+	if input == nil {
+		return nil
+	}
+	return &synthetics.CanaryRunConfigOutput{
+		ActiveTracing:    input.ActiveTracing,
+		MemoryInMB:       input.MemoryInMB,
+		TimeoutInSeconds: input.TimeoutInSeconds,
+	}
+}
+
+func vpcConfigOutput(input *synthetics.VpcConfigInput) *synthetics.VpcConfigOutput {
+	if input == nil {
+		return nil
+	}
+	return &synthetics.VpcConfigOutput{
+		SecurityGroupIds: input.SecurityGroupIds,
+		SubnetIds:        input.SubnetIds,
+		VpcId:            aws.String("vpc-00000000"),
+	}
+}
+
+// CreateCanaryWithContext creates a canary in CREATING state
+func (s *Synthetics) CreateCanaryWithContext(ctx aws.Context, input *synthetics.CreateCanaryInput, opts ...request.Option) (*synthetics.CreateCanaryOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	if _, ok := s.backend.Canaries[*input.Name]; ok {
+		return &synthetics.CreateCanaryOutput{}, newError(synthetics.ErrCodeConflictException, "Canary %s already exists", *input.Name)
+	}
+
+	canary := &Canary{
+		Canary: &synthetics.Canary{
+			Id:                           aws.String(fmt.Sprintf("%08d-1111-4000-8000-000000000000", len(s.backend.Canaries)+1)),
+			Name:                         input.Name,
+			ArtifactS3Location:           input.ArtifactS3Location,
+			ExecutionRoleArn:             input.ExecutionRoleArn,
+			FailureRetentionPeriodInDays: input.FailureRetentionPeriodInDays,
+			SuccessRetentionPeriodInDays: input.SuccessRetentionPeriodInDays,
+			RuntimeVersion:               input.RuntimeVersion,
+			ArtifactConfig:               artifactConfigOutput(input.ArtifactConfig),
+			Code: &synthetics.CanaryCodeOutput{
+				Handler:           input.Code.Handler,
+				SourceLocationArn: aws.String(fmt.Sprintf("arn:aws:lambda:%s:%s:layer:cwsyn-%s:1", s.backend.Region, s.backend.AccountID, *input.Name)),
+			},
+			RunConfig: runConfigOutput(input.RunConfig),
+			VpcConfig: vpcConfigOutput(input.VpcConfig),
+			Schedule: &synthetics.CanaryScheduleOutput{
+				DurationInSeconds: input.Schedule.DurationInSeconds,
+				Expression:        input.Schedule.Expression,
+			},
+			Status: &synthetics.CanaryStatus{
+				State:       aws.String(synthetics.CanaryStateCreating),
+				StateReason: aws.String(""),
+			},
+		},
+		Runs: []*synthetics.CanaryRun{},
+		Tags: input.Tags,
+		Code: input.Code,
+	}
+	if canary.Tags == nil {
+		canary.Tags = map[string]*string{}
+	}
+	if input.RunConfig != nil {
+		canary.EnvironmentVariables = input.RunConfig.EnvironmentVariables
+	}
+	s.backend.Canaries[*input.Name] = canary
+
+	// Simulate Lambda resources created by Synthetics
+	lambdaName := fmt.Sprintf("cwsyn-%s-%s", *input.Name, *canary.Canary.Id)
+	canary.Canary.EngineArn = aws.String(fmt.Sprintf("arn:aws:lambda:%s:%s:function:%s:1", s.backend.Region, s.backend.AccountID, lambdaName))
+	s.backend.Lambdas[lambdaName] = true
+	s.backend.Layers[lambdaName] = []int64{1}
+
+	return &synthetics.CreateCanaryOutput{Canary: canary.Canary}, nil
+}
+
+// UpdateCanaryWithContext updates a canary moving it in UPDATING state
+func (s *Synthetics) UpdateCanaryWithContext(ctx aws.Context, input *synthetics.UpdateCanaryInput, opts ...request.Option) (*synthetics.UpdateCanaryOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	canary, ok := s.backend.Canaries[*input.Name]
+	if !ok {
+		return &synthetics.UpdateCanaryOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", *input.Name)
+	}
+
+	// Check canary state
+	state := *canary.Canary.Status.State
+	if state == synthetics.CanaryStateCreating || state == synthetics.CanaryStateUpdating || state == synthetics.CanaryStateRunning {
+		return &synthetics.UpdateCanaryOutput{}, newError(synthetics.ErrCodeConflictException, "Canary %s is in %s state, can't update it", *input.Name, state)
+	}
+
+	// Omitted fields are not changed
+	if input.ExecutionRoleArn != nil {
+		canary.Canary.ExecutionRoleArn = input.ExecutionRoleArn
+	}
+	if input.RuntimeVersion != nil {
+		canary.Canary.RuntimeVersion = input.RuntimeVersion
+	}
+	if input.Code != nil {
+		canary.Canary.Code.Handler = input.Code.Handler
+		canary.Code = input.Code
+	}
+	if input.RunConfig != nil {
+		canary.Canary.RunConfig = runConfigOutput(input.RunConfig)
+		canary.EnvironmentVariables = input.RunConfig.EnvironmentVariables
+	}
+	if input.Schedule != nil {
+		canary.Canary.Schedule.DurationInSeconds = input.Schedule.DurationInSeconds
+		canary.Canary.Schedule.Expression = input.Schedule.Expression
+	}
+	if input.VisualReference != nil {
+		baseRunID := *input.VisualReference.BaseCanaryRunId
+		if baseRunID == "lastrun" && len(canary.Runs) > 0 {
+			baseRunID = *canary.Runs[0].Id
+		}
+		canary.Canary.VisualReference = &synthetics.VisualReferenceOutput_{
+			BaseCanaryRunId: aws.String(baseRunID),
+			BaseScreenshots: input.VisualReference.BaseScreenshots,
+		}
+	}
+	if input.FailureRetentionPeriodInDays != nil {
+		canary.Canary.FailureRetentionPeriodInDays = input.FailureRetentionPeriodInDays
+	}
+	if input.SuccessRetentionPeriodInDays != nil {
+		canary.Canary.SuccessRetentionPeriodInDays = input.SuccessRetentionPeriodInDays
+	}
+	if input.VpcConfig != nil {
+		canary.Canary.VpcConfig = vpcConfigOutput(input.VpcConfig)
+	}
+	canary.Canary.Status.State = aws.String(synthetics.CanaryStateUpdating)
+	if input.ArtifactConfig != nil {
+		canary.Canary.ArtifactConfig = artifactConfigOutput(input.ArtifactConfig)
+	}
+
+	return &synthetics.UpdateCanaryOutput{}, nil
+}
+
+// GetCanaryWithContext return the canary, every call advance transitional states
+func (s *Synthetics) GetCanaryWithContext(ctx aws.Context, input *synthetics.GetCanaryInput, opts ...request.Option) (*synthetics.GetCanaryOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	canary, ok := s.backend.Canaries[*input.Name]
+	if !ok {
+		return &synthetics.GetCanaryOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", *input.Name)
+	}
+
+	// Return a copy of current state
+	current := *canary.Canary
+	current.Status = &synthetics.CanaryStatus{
+		State:       aws.String(*canary.Canary.Status.State),
+		StateReason: canary.Canary.Status.StateReason,
+	}
+	current.Tags = canary.Tags
+	output := &synthetics.GetCanaryOutput{Canary: &current}
+	s.backend.advance(canary)
+
+	return output, nil
+}
+
+// DescribeCanariesWithContext return all canaries, sorted by name
+func (s *Synthetics) DescribeCanariesWithContext(ctx aws.Context, input *synthetics.DescribeCanariesInput, opts ...request.Option) (*synthetics.DescribeCanariesOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	names := []string{}
+	for name := range s.backend.Canaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	output := &synthetics.DescribeCanariesOutput{}
+	for _, name := range names {
+		current := *s.backend.Canaries[name].Canary
+		current.Tags = s.backend.Canaries[name].Tags
+		output.Canaries = append(output.Canaries, &current)
+	}
+	return output, nil
+}
+
+// DeleteCanaryWithContext delete a canary
+func (s *Synthetics) DeleteCanaryWithContext(ctx aws.Context, input *synthetics.DeleteCanaryInput, opts ...request.Option) (*synthetics.DeleteCanaryOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	canary, ok := s.backend.Canaries[*input.Name]
+	if !ok {
+		return &synthetics.DeleteCanaryOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", *input.Name)
+	}
+	if *canary.Canary.Status.State == synthetics.CanaryStateRunning {
+		return &synthetics.DeleteCanaryOutput{}, newError(synthetics.ErrCodeConflictException, "Canary %s is running", *input.Name)
+	}
+
+	delete(s.backend.Canaries, *input.Name)
+	return &synthetics.DeleteCanaryOutput{}, nil
+}
+
+// StartCanaryWithContext start a canary
+func (s *Synthetics) StartCanaryWithContext(ctx aws.Context, input *synthetics.StartCanaryInput, opts ...request.Option) (*synthetics.StartCanaryOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	canary, ok := s.backend.Canaries[*input.Name]
+	if !ok {
+		return &synthetics.StartCanaryOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", *input.Name)
+	}
+
+	state := *canary.Canary.Status.State
+	if state != synthetics.CanaryStateReady && state != synthetics.CanaryStateStopped {
+		return &synthetics.StartCanaryOutput{}, newError(synthetics.ErrCodeConflictException, "Canary %s is in %s state, can't start it", *input.Name, state)
+	}
+
+	canary.Canary.Status.State = aws.String(synthetics.CanaryStateStarting)
+	return &synthetics.StartCanaryOutput{}, nil
+}
+
+// StopCanaryWithContext stop a canary
+func (s *Synthetics) StopCanaryWithContext(ctx aws.Context, input *synthetics.StopCanaryInput, opts ...request.Option) (*synthetics.StopCanaryOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	canary, ok := s.backend.Canaries[*input.Name]
+	if !ok {
+		return &synthetics.StopCanaryOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", *input.Name)
+	}
+
+	state := *canary.Canary.Status.State
+	if state != synthetics.CanaryStateRunning {
+		return &synthetics.StopCanaryOutput{}, newError(synthetics.ErrCodeConflictException, "Canary %s is in %s state, can't stop it", *input.Name, state)
+	}
+
+	canary.Canary.Status.State = aws.String(synthetics.CanaryStateStopping)
+	return &synthetics.StopCanaryOutput{}, nil
+}
+
+// GetCanaryRunsWithContext return canary runs, newest first, the first page advance transitional states
+func (s *Synthetics) GetCanaryRunsWithContext(ctx aws.Context, input *synthetics.GetCanaryRunsInput, opts ...request.Option) (*synthetics.GetCanaryRunsOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	canary, ok := s.backend.Canaries[*input.Name]
+	if !ok {
+		return &synthetics.GetCanaryRunsOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", *input.Name)
+	}
+
+	// Paginate runs
+	start := 0
+	if input.NextToken != nil {
+		start, _ = strconv.Atoi(*input.NextToken)
+	} else {
+		s.backend.advance(canary)
+	}
+	end := len(canary.Runs)
+	if input.MaxResults != nil && start+int(*input.MaxResults) < end {
+		end = start + int(*input.MaxResults)
+	}
+	if start > end {
+		start = end
+	}
+
+	output := &synthetics.GetCanaryRunsOutput{
+		CanaryRuns: canary.Runs[start:end],
+	}
+	if end < len(canary.Runs) {
+		output.NextToken = aws.String(strconv.Itoa(end))
+	}
+
+	return output, nil
+}
+
+// ListTagsForResourceWithContext return canary tags
+func (s *Synthetics) ListTagsForResourceWithContext(ctx aws.Context, input *synthetics.ListTagsForResourceInput, opts ...request.Option) (*synthetics.ListTagsForResourceOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	name := canaryNameFromArn(input.ResourceArn)
+	canary, ok := s.backend.Canaries[name]
+	if !ok {
+		return &synthetics.ListTagsForResourceOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", name)
+	}
+
+	tags := map[string]*string{}
+	for key, value := range canary.Tags {
+		tags[key] = value
+	}
+
+	return &synthetics.ListTagsForResourceOutput{Tags: tags}, nil
+}
+
+// TagResourceWithContext add canary tags
+func (s *Synthetics) TagResourceWithContext(ctx aws.Context, input *synthetics.TagResourceInput, opts ...request.Option) (*synthetics.TagResourceOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	name := canaryNameFromArn(input.ResourceArn)
+	canary, ok := s.backend.Canaries[name]
+	if !ok {
+		return &synthetics.TagResourceOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", name)
+	}
+
+	for key, value := range input.Tags {
+		canary.Tags[key] = value
+	}
+
+	return &synthetics.TagResourceOutput{}, nil
+}
+
+// UntagResourceWithContext remove canary tags
+func (s *Synthetics) UntagResourceWithContext(ctx aws.Context, input *synthetics.UntagResourceInput, opts ...request.Option) (*synthetics.UntagResourceOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	name := canaryNameFromArn(input.ResourceArn)
+	canary, ok := s.backend.Canaries[name]
+	if !ok {
+		return &synthetics.UntagResourceOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", name)
+	}
+
+	for _, key := range input.TagKeys {
+		delete(canary.Tags, *key)
+	}
+
+	return &synthetics.UntagResourceOutput{}, nil
+}
+
+func newRuntime(name string, releaseDate string, deprecationDate string) *synthetics.RuntimeVersion {
+	runtime := &synthetics.RuntimeVersion{
+		VersionName: aws.String(name),
+		Description: aws.String(fmt.Sprintf("Synthetics runtime %s", name)),
+	}
+	if released, err := time.Parse("2006-01-02", releaseDate); err == nil {
+		runtime.ReleaseDate = &released
+	}
+	if deprecated, err := time.Parse("2006-01-02", deprecationDate); err == nil {
+		runtime.DeprecationDate = &deprecated
+	}
+	return runtime
+}
+
+// DescribeRuntimeVersionsWithContext return available runtimes
+func (s *Synthetics) DescribeRuntimeVersionsWithContext(ctx aws.Context, input *synthetics.DescribeRuntimeVersionsInput, opts ...request.Option) (*synthetics.DescribeRuntimeVersionsOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	return &synthetics.DescribeRuntimeVersionsOutput{
+		RuntimeVersions: s.backend.Runtimes,
+	}, nil
+}
+
+// SyntheticsDryRun is an in-memory implementation of the Synthetics dry runs API,
+// a dry run completes immediately and fails when the canary runs must fail
+type SyntheticsDryRun struct {
+	backend *Backend
+}
+
+// StartCanaryDryRunWithContext start a dry run of an existing canary
+func (s *SyntheticsDryRun) StartCanaryDryRunWithContext(ctx aws.Context, input *awsinternal.StartCanaryDryRunInput, opts ...request.Option) (*awsinternal.StartCanaryDryRunOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	canary, ok := s.backend.Canaries[*input.Name]
+	if !ok {
+		return &awsinternal.StartCanaryDryRunOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", *input.Name)
+	}
+	state := *canary.Canary.Status.State
+	if state == synthetics.CanaryStateCreating || state == synthetics.CanaryStateUpdating {
+		return &awsinternal.StartCanaryDryRunOutput{}, newError(synthetics.ErrCodeConflictException, "Canary %s is in %s state, can't start a dry run", *input.Name, state)
+	}
+
+	// Elaborate run result
+	runState := synthetics.CanaryRunStatePassed
+	reason := ""
+	if failure, ok := s.backend.FailingRuns[*input.Name]; ok {
+		runState = synthetics.CanaryRunStateFailed
+		reason = failure
+	}
+
+	if canary.DryRuns == nil {
+		canary.DryRuns = map[string][]*synthetics.CanaryRun{}
+	}
+	dryRunID := fmt.Sprintf("%08d-0000-4000-9000-000000000000", len(canary.DryRuns)+1)
+	now := time.Now().Add(s.backend.ClockSkew)
+
+	// Write dry run log into artifact bucket
+	artifactLocation := canary.Canary.ArtifactS3Location
+	if input.ArtifactS3Location != nil {
+		artifactLocation = input.ArtifactS3Location
+	}
+	location := strings.TrimPrefix(*artifactLocation, "s3://") + "/" + dryRunID
+	bucketName := strings.Split(location, "/")[0]
+	if objects, ok := s.backend.Buckets[bucketName]; ok {
+		objects[location[len(bucketName)+1:]+"/log.txt"] = []byte(fmt.Sprintf("INFO: Canary %s dry run %s %s %s", *input.Name, dryRunID, runState, reason))
+	}
+
+	canary.DryRuns[dryRunID] = []*synthetics.CanaryRun{
+		{
+			Id:                 aws.String(dryRunID),
+			Name:               input.Name,
+			ArtifactS3Location: aws.String(location),
+			Status: &synthetics.CanaryRunStatus{
+				State:       aws.String(runState),
+				StateReason: aws.String(reason),
+			},
+			Timeline: &synthetics.CanaryRunTimeline{
+				Started:   aws.Time(now),
+				Completed: aws.Time(now),
+			},
+		},
+	}
+
+	return &awsinternal.StartCanaryDryRunOutput{
+		DryRunConfig: &awsinternal.DryRunConfigOutput{
+			DryRunId:     aws.String(dryRunID),
+			LastModified: aws.Time(now),
+		},
+	}, nil
+}
+
+// GetCanaryDryRunRunsWithContext return runs of a dry run
+func (s *SyntheticsDryRun) GetCanaryDryRunRunsWithContext(ctx aws.Context, input *awsinternal.GetCanaryDryRunRunsInput, opts ...request.Option) (*synthetics.GetCanaryRunsOutput, error) {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+
+	canary, ok := s.backend.Canaries[*input.Name]
+	if !ok {
+		return &synthetics.GetCanaryRunsOutput{}, newError(synthetics.ErrCodeResourceNotFoundException, "Canary %s not found", *input.Name)
+	}
+	runs, ok := canary.DryRuns[aws.StringValue(input.DryRunId)]
+	if !ok {
+		return &synthetics.GetCanaryRunsOutput{}, newError(synthetics.ErrCodeValidationException, "Dry run %s not found", aws.StringValue(input.DryRunId))
+	}
+
+	return &synthetics.GetCanaryRunsOutput{CanaryRuns: runs}, nil
+}
@@ -0,0 +1,186 @@
+package group
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/synthetics"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+)
+
+// Group structure
+type Group struct {
+	clients *awsinternal.Clients
+
+	Name *string
+	Arn  *string
+	Tags map[string]string
+}
+
+// New creates a new Group
+func New(clients *awsinternal.Clients, name *string) *Group {
+	return &Group{
+		clients: clients,
+
+		Name: name,
+		Tags: map[string]string{},
+	}
+}
+
+// List return all groups in current AWS account and region
+func List(ctx context.Context, clients *awsinternal.Clients) ([]*Group, error) {
+	groups := []*Group{}
+
+	var nextToken *string
+	for {
+		res, err := clients.Synthetics.ListGroupsWithContext(ctx, &synthetics.ListGroupsInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return groups, err
+		}
+
+		for _, summary := range res.Groups {
+			group := New(clients, summary.Name)
+			group.Arn = summary.Arn
+			groups = append(groups, group)
+		}
+
+		if res.NextToken == nil {
+			return groups, nil
+		}
+		nextToken = res.NextToken
+	}
+}
+
+// ListAssociated return the names of groups the resource is associated with
+func ListAssociated(ctx context.Context, clients *awsinternal.Clients, resourceArn string) ([]string, error) {
+	names := []string{}
+
+	var nextToken *string
+	for {
+		res, err := clients.Synthetics.ListAssociatedGroupsWithContext(ctx, &synthetics.ListAssociatedGroupsInput{
+			ResourceArn: aws.String(resourceArn),
+			NextToken:   nextToken,
+		})
+		if err != nil {
+			return names, err
+		}
+
+		for _, summary := range res.Groups {
+			names = append(names, *summary.Name)
+		}
+
+		if res.NextToken == nil {
+			return names, nil
+		}
+		nextToken = res.NextToken
+	}
+}
+
+// IsDeployed check if Synthetics Group is present in current AWS account
+func (g *Group) IsDeployed(ctx context.Context) bool {
+	res, err := g.clients.Synthetics.GetGroupWithContext(ctx, &synthetics.GetGroupInput{
+		GroupIdentifier: g.Name,
+	})
+	if err != nil {
+		return false
+	}
+
+	g.Arn = res.Group.Arn
+	g.Tags = aws.StringValueMap(res.Group.Tags)
+	return true
+}
+
+// IsManaged check if group was created by the CLI
+func (g *Group) IsManaged() bool {
+	return g.Tags[awsinternal.ManagedTag] == "true"
+}
+
+// Deploy create group if not exist
+func (g *Group) Deploy(ctx context.Context) error {
+	if g.IsDeployed(ctx) {
+		return nil
+	}
+
+	g.Tags[awsinternal.ManagedTag] = "true"
+	res, err := g.clients.Synthetics.CreateGroupWithContext(ctx, &synthetics.CreateGroupInput{
+		Name: g.Name,
+		Tags: aws.StringMap(g.Tags),
+	})
+	if err != nil {
+		// Group can be created in the meantime by a parallel deploy
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == synthetics.ErrCodeConflictException && g.IsDeployed(ctx) {
+			return nil
+		}
+		return err
+	}
+
+	g.Arn = res.Group.Arn
+	return nil
+}
+
+// GetResources return the ARNs of resources associated to the group
+func (g *Group) GetResources(ctx context.Context) ([]string, error) {
+	resources := []string{}
+
+	var nextToken *string
+	for {
+		res, err := g.clients.Synthetics.ListGroupResourcesWithContext(ctx, &synthetics.ListGroupResourcesInput{
+			GroupIdentifier: g.Name,
+			NextToken:       nextToken,
+		})
+		if err != nil {
+			return resources, err
+		}
+
+		resources = append(resources, aws.StringValueSlice(res.Resources)...)
+
+		if res.NextToken == nil {
+			return resources, nil
+		}
+		nextToken = res.NextToken
+	}
+}
+
+// Associate add resource to group
+func (g *Group) Associate(ctx context.Context, resourceArn string) error {
+	_, err := g.clients.Synthetics.AssociateResourceWithContext(ctx, &synthetics.AssociateResourceInput{
+		GroupIdentifier: g.Name,
+		ResourceArn:     aws.String(resourceArn),
+	})
+	return err
+}
+
+// Disassociate remove resource from group
+func (g *Group) Disassociate(ctx context.Context, resourceArn string) error {
+	_, err := g.clients.Synthetics.DisassociateResourceWithContext(ctx, &synthetics.DisassociateResourceInput{
+		GroupIdentifier: g.Name,
+		ResourceArn:     aws.String(resourceArn),
+	})
+	return err
+}
+
+// Remove group
+func (g *Group) Remove(ctx context.Context) error {
+	_, err := g.clients.Synthetics.DeleteGroupWithContext(ctx, &synthetics.DeleteGroupInput{
+		GroupIdentifier: g.Name,
+	})
+	return err
+}
+
+// RemoveIfEmpty remove group when it was created by the CLI and has no resources associated,
+// return true if the group was removed
+func (g *Group) RemoveIfEmpty(ctx context.Context) (bool, error) {
+	if g.IsDeployed(ctx) == false || g.IsManaged() == false {
+		return false, nil
+	}
+
+	resources, err := g.GetResources(ctx)
+	if err != nil || len(resources) > 0 {
+		return false, err
+	}
+
+	return true, g.Remove(ctx)
+}
@@ -1,11 +1,12 @@
 package iam
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
 	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
 	"github.com/daaru00/aws-canary-cli/internal/bucket"
@@ -13,7 +14,7 @@ import (
 
 // Policy structure
 type Policy struct {
-	clients    *clients
+	clients    *awsinternal.Clients
 	statements []StatementEntry
 
 	Name *string
@@ -40,12 +41,12 @@ type Condition struct {
 }
 
 // NewPolicy creates a new IAM Policy for Canary
-func NewPolicy(ses *session.Session, nameOrArn *string) *Policy {
+func NewPolicy(clients *awsinternal.Clients, nameOrArn *string) *Policy {
 	var arn string
 	var name string
 
 	// Get account id
-	accountID := awsinternal.GetCallerAccountID(ses)
+	accountID := awsinternal.GetCallerAccountID(clients)
 
 	// Check if an arn is provided
 	if len(*nameOrArn) > 0 {
@@ -61,9 +62,7 @@ func NewPolicy(ses *session.Session, nameOrArn *string) *Policy {
 	}
 
 	return &Policy{
-		clients: &clients{
-			iam: iam.New(ses),
-		},
+		clients:    clients,
 		statements: []StatementEntry{},
 
 		Name: &name,
@@ -72,8 +71,8 @@ func NewPolicy(ses *session.Session, nameOrArn *string) *Policy {
 }
 
 // IsDeployed check if IAM Policy name is present in current AWS account
-func (p *Policy) IsDeployed() bool {
-	_, err := p.clients.iam.GetPolicy(&iam.GetPolicyInput{
+func (p *Policy) IsDeployed(ctx context.Context) bool {
+	_, err := p.clients.IAM.GetPolicyWithContext(ctx, &iam.GetPolicyInput{
 		PolicyArn: p.Arn,
 	})
 
@@ -118,6 +117,22 @@ func (p *Policy) AddArtifactBucketPermission(artifactBucket *bucket.Bucket) {
 	}, p.statements...)
 }
 
+// AddKMSPermission add KMS permissions statements to policy, required to encrypt artifacts
+func (p *Policy) AddKMSPermission(kmsKeyArn *string) {
+	p.statements = append([]StatementEntry{
+		{
+			Effect: "Allow",
+			Action: []string{
+				"kms:GenerateDataKey",
+				"kms:Decrypt",
+			},
+			Resource: []string{
+				*kmsKeyArn,
+			},
+		},
+	}, p.statements...)
+}
+
 // AddXRayPermission add xray permissions statements to policy
 func (p *Policy) AddXRayPermission() {
 	p.statements = append([]StatementEntry{
@@ -163,7 +178,7 @@ func (p *Policy) AddMetricsPermission() {
 			},
 			Condition: Condition{
 				StringEquals: map[string]string{
-					"cloudwatch:namespace": "CloudWatchSynthetics",
+					"cloudwatch:namespace": awsinternal.MetricsNamespace,
 				},
 			},
 		},
@@ -223,3 +238,54 @@ func (p *Policy) Render() (*string, error) {
 	str = string(doc)
 	return &str, err
 }
+
+// IsEquivalent check if the provided policy document grants the same permissions of the policy,
+// single values and lists are considered equivalent as IAM does
+func (p *Policy) IsEquivalent(document string) (bool, error) {
+	rendered, err := p.Render()
+	if err != nil {
+		return false, err
+	}
+
+	var expected, actual interface{}
+	err = json.Unmarshal([]byte(*rendered), &expected)
+	if err != nil {
+		return false, err
+	}
+	err = json.Unmarshal([]byte(document), &actual)
+	if err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(normalizePolicyValue(expected), normalizePolicyValue(actual)), nil
+}
+
+// normalizePolicyValue convert single string values into lists and remove empty objects
+func normalizePolicyValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case []interface{}:
+		normalized := []interface{}{}
+		for _, item := range typed {
+			normalized = append(normalized, normalizePolicyValue(item))
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := map[string]interface{}{}
+		for key, item := range typed {
+			switch itemTyped := item.(type) {
+			case map[string]interface{}:
+				if len(itemTyped) == 0 {
+					continue
+				}
+			case string:
+				// Policy version and statements effect are plain strings
+				if key != "Version" && key != "Effect" {
+					item = []interface{}{itemTyped}
+				}
+			}
+			normalized[key] = normalizePolicyValue(item)
+		}
+		return normalized
+	}
+	return value
+}
@@ -1,32 +1,33 @@
 package iam
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"strings"
-	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
 	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
 )
 
 // Role structure
 type Role struct {
-	clients *clients
+	clients *awsinternal.Clients
 
 	Name         *string
 	Arn          *string
 	InlinePolicy *Policy
+	Tags         map[string]string
 }
 
 // NewRole creates a new IAM Role for Canary
-func NewRole(ses *session.Session, nameOrArn *string) *Role {
+func NewRole(clients *awsinternal.Clients, nameOrArn *string) *Role {
 	var arn string
 	var name string
 
 	// Get account id
-	accountID := awsinternal.GetCallerAccountID(ses)
+	accountID := awsinternal.GetCallerAccountID(clients)
 
 	// Check if an arn is provided
 	if strings.HasPrefix(*nameOrArn, "arn:") == true {
@@ -40,18 +41,43 @@ func NewRole(ses *session.Session, nameOrArn *string) *Role {
 	}
 
 	return &Role{
-		clients: &clients{
-			iam.New(ses),
-		},
+		clients:      clients,
 		Name:         &name,
 		Arn:          &arn,
 		InlinePolicy: nil,
+		Tags:         map[string]string{},
+	}
+}
+
+// ListRoles return roles whose name starts with prefix
+func ListRoles(ctx context.Context, clients *awsinternal.Clients, prefix string) ([]*Role, error) {
+	roles := []*Role{}
+
+	var marker *string
+	for {
+		res, err := clients.IAM.ListRolesWithContext(ctx, &iam.ListRolesInput{
+			Marker: marker,
+		})
+		if err != nil {
+			return roles, err
+		}
+
+		for _, role := range res.Roles {
+			if strings.HasPrefix(*role.RoleName, prefix) {
+				roles = append(roles, NewRole(clients, role.RoleName))
+			}
+		}
+
+		if aws.BoolValue(res.IsTruncated) == false {
+			return roles, nil
+		}
+		marker = res.Marker
 	}
 }
 
 // IsDeployed check if IAM Role name is present in current AWS account
-func (r *Role) IsDeployed() bool {
-	_, err := r.clients.iam.GetRole(&iam.GetRoleInput{
+func (r *Role) IsDeployed(ctx context.Context) bool {
+	_, err := r.clients.IAM.GetRoleWithContext(ctx, &iam.GetRoleInput{
 		RoleName: r.Name,
 	})
 	return err == nil
@@ -64,14 +90,13 @@ func (r *Role) SetInlinePolicy(policy *Policy) error {
 }
 
 // Deploy IAM role
-func (r *Role) Deploy() error {
-	var needWait bool
-
+func (r *Role) Deploy(ctx context.Context) error {
 	// Check if not deployed
-	if r.IsDeployed() == false {
+	if r.IsDeployed(ctx) == false {
 		// Create role
-		_, err := r.clients.iam.CreateRole(&iam.CreateRoleInput{
+		_, err := r.clients.IAM.CreateRoleWithContext(ctx, &iam.CreateRoleInput{
 			RoleName: r.Name,
+			Tags:     r.getTags(),
 			AssumeRolePolicyDocument: aws.String(`{
 				"Version": "2012-10-17",
 				"Statement": [{
@@ -92,15 +117,23 @@ func (r *Role) Deploy() error {
 		}
 
 		// Wait until policy is fully created
-		err = r.clients.iam.WaitUntilRoleExists(&iam.GetRoleInput{
+		err = r.clients.IAM.WaitUntilRoleExistsWithContext(ctx, &iam.GetRoleInput{
 			RoleName: r.Name,
 		})
 		if err != nil {
 			return err
 		}
+	}
 
-		// Set a dummy sleep to avoid IAM role not recognized as valid Lambda role
-		needWait = true
+	// Update tags, existing ones are not removed
+	if len(r.Tags) > 0 {
+		_, err := r.clients.IAM.TagRoleWithContext(ctx, &iam.TagRoleInput{
+			RoleName: r.Name,
+			Tags:     r.getTags(),
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	// Check for inline policy
@@ -112,7 +145,7 @@ func (r *Role) Deploy() error {
 		}
 
 		// Add role policy
-		_, err = r.clients.iam.PutRolePolicy(&iam.PutRolePolicyInput{
+		_, err = r.clients.IAM.PutRolePolicyWithContext(ctx, &iam.PutRolePolicyInput{
 			RoleName:       r.Name,
 			PolicyName:     r.InlinePolicy.Name,
 			PolicyDocument: policyDoc,
@@ -122,18 +155,13 @@ func (r *Role) Deploy() error {
 		}
 	}
 
-	// Do a dummy sleep to avoid IAM role not recognized as valida Lambda role.
-	if needWait {
-		time.Sleep(10 * 1000 * time.Millisecond) // Yes, 10 is a magic number.
-	}
-
 	return nil
 }
 
 // Remove IAM role
-func (r *Role) Remove() error {
+func (r *Role) Remove(ctx context.Context) error {
 	// Get role inline policy
-	_, err := r.clients.iam.GetRolePolicy(&iam.GetRolePolicyInput{
+	_, err := r.clients.IAM.GetRolePolicyWithContext(ctx, &iam.GetRolePolicyInput{
 		RoleName:   r.Name,
 		PolicyName: r.InlinePolicy.Name,
 	})
@@ -142,7 +170,7 @@ func (r *Role) Remove() error {
 	}
 
 	// Delete inline policy
-	_, err = r.clients.iam.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+	_, err = r.clients.IAM.DeleteRolePolicyWithContext(ctx, &iam.DeleteRolePolicyInput{
 		RoleName:   r.Name,
 		PolicyName: r.InlinePolicy.Name,
 	})
@@ -151,7 +179,7 @@ func (r *Role) Remove() error {
 	}
 
 	// Delete role
-	_, err = r.clients.iam.DeleteRole(&iam.DeleteRoleInput{
+	_, err = r.clients.IAM.DeleteRoleWithContext(ctx, &iam.DeleteRoleInput{
 		RoleName: r.Name,
 	})
 	if err != nil {
@@ -160,3 +188,44 @@ func (r *Role) Remove() error {
 
 	return nil
 }
+
+// GetInlinePolicyDocument return the deployed inline policy document
+func (r *Role) GetInlinePolicyDocument(ctx context.Context, policyName *string) (string, error) {
+	res, err := r.clients.IAM.GetRolePolicyWithContext(ctx, &iam.GetRolePolicyInput{
+		RoleName:   r.Name,
+		PolicyName: policyName,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Policy document is returned URL encoded
+	return url.QueryUnescape(*res.PolicyDocument)
+}
+
+// GetDeployedTags return tags of deployed role
+func (r *Role) GetDeployedTags(ctx context.Context) (map[string]string, error) {
+	res, err := r.clients.IAM.ListRoleTagsWithContext(ctx, &iam.ListRoleTagsInput{
+		RoleName: r.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string]string{}
+	for _, tag := range res.Tags {
+		tags[*tag.Key] = *tag.Value
+	}
+	return tags, nil
+}
+
+func (r *Role) getTags() []*iam.Tag {
+	tags := []*iam.Tag{}
+	for key, value := range r.Tags {
+		tags = append(tags, &iam.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+	return tags
+}
@@ -0,0 +1,214 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+)
+
+//go:embed shims/nodejs
+//go:embed shims/python/runner.py shims/python/aws_synthetics shims/python/aws_synthetics/__init__.py
+//go:embed shims/python/aws_synthetics/common/__init__.py shims/python/aws_synthetics/selenium/__init__.py
+var shims embed.FS
+
+// Result structure
+type Result struct {
+	RunID        string
+	Passed       bool
+	Reason       string
+	ArtifactsDir string
+}
+
+// report structure, the subset of SyntheticsReport written by local runners
+type report struct {
+	Status        string `json:"status"`
+	FailureReason string `json:"failureReason"`
+}
+
+// Run execute canary handler on the local machine, artifacts are written
+// into a run directory inside outDir that mirrors the S3 artifacts layout
+func Run(ctx context.Context, canary *canary.Canary, outDir string) (*Result, error) {
+	result := &Result{
+		RunID: newRunID(),
+	}
+
+	// Check runtime
+	if canary.IsNodeRuntime() == false && canary.IsPythonRuntime() == false {
+		return result, fmt.Errorf("[%s] Error: runtime %s not supported", canary.Name, canary.RuntimeVersion)
+	}
+
+	// Prepare artifacts directory
+	now := time.Now().UTC()
+	result.ArtifactsDir = path.Join(outDir, "canary", canary.Name, fmt.Sprintf("%s-%03d", now.Format("2006/01/02/15/04-05"), now.Nanosecond()/int(time.Millisecond)))
+	err := os.MkdirAll(result.ArtifactsDir, 0755)
+	if err != nil {
+		return result, err
+	}
+
+	// Extract runtime shims
+	shimsDir, err := ioutil.TempDir("", "aws-canary-shims-")
+	if err != nil {
+		return result, err
+	}
+	defer os.RemoveAll(shimsDir)
+	err = extractShims(shimsDir)
+	if err != nil {
+		return result, err
+	}
+
+	// Elaborate code absolute path
+	src, err := filepath.Abs(canary.Code.Src)
+	if err != nil {
+		return result, err
+	}
+	artifactsDir, err := filepath.Abs(result.ArtifactsDir)
+	if err != nil {
+		return result, err
+	}
+
+	// Setup canary timeout
+	if canary.TimeoutInSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(canary.TimeoutInSeconds)*time.Second)
+		defer cancel()
+	}
+
+	// Prepare runner command
+	var cmd *exec.Cmd
+	env := os.Environ()
+	if canary.IsNodeRuntime() {
+		cmd = exec.CommandContext(ctx, "node", path.Join(shimsDir, "nodejs", "runner.js"))
+		env = append(env, "NODE_PATH="+path.Join(shimsDir, "nodejs", "node_modules"))
+	} else {
+		cmd = exec.CommandContext(ctx, pythonExecutable(), path.Join(shimsDir, "python", "runner.py"))
+		env = append(env, "PYTHONPATH="+path.Join(shimsDir, "python"))
+	}
+
+	// Inject canary environment variables
+	for key, value := range canary.EnvironmentVariables {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+	cmd.Env = append(env,
+		"CANARY_NAME="+canary.Name,
+		"CANARY_RUN_ID="+result.RunID,
+		"CANARY_RUNTIME="+canary.RuntimeVersion,
+		"CANARY_HANDLER="+canary.Code.Handler,
+		"CANARY_SRC="+src,
+		"CANARY_ARTIFACTS_DIR="+artifactsDir,
+	)
+	cmd.Dir = src
+
+	// Print runner output as canary logs
+	output := &logWriter{canary: canary}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	// Run canary handler
+	runErr := cmd.Run()
+	output.Flush()
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+
+	// Read run report
+	reportPaths, _ := filepath.Glob(path.Join(artifactsDir, "SyntheticsReport-*.json"))
+	if len(reportPaths) == 0 {
+		if runErr != nil {
+			return result, fmt.Errorf("[%s] Error: local runner failed: %s", canary.Name, runErr)
+		}
+		return result, fmt.Errorf("[%s] Error: local runner did not write a report", canary.Name)
+	}
+	data, err := ioutil.ReadFile(reportPaths[0])
+	if err != nil {
+		return result, err
+	}
+	runReport := report{}
+	err = json.Unmarshal(data, &runReport)
+	if err != nil {
+		return result, err
+	}
+
+	result.Passed = runReport.Status == "PASSED"
+	result.Reason = runReport.FailureReason
+	return result, nil
+}
+
+func extractShims(destination string) error {
+	return fs.WalkDir(shims, "shims", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		// Node modules are resolved from a node_modules directory
+		relPath := strings.TrimPrefix(filePath, "shims/")
+		if strings.HasPrefix(relPath, "nodejs/") && path.Base(relPath) != "runner.js" {
+			relPath = path.Join("nodejs", "node_modules", strings.TrimPrefix(relPath, "nodejs/"))
+		}
+
+		// Write shim file
+		content, err := shims.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		destPath := path.Join(destination, relPath)
+		err = os.MkdirAll(path.Dir(destPath), 0755)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(destPath, content, 0644)
+	})
+}
+
+func pythonExecutable() string {
+	if _, err := exec.LookPath("python3"); err == nil {
+		return "python3"
+	}
+	return "python"
+}
+
+func newRunID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// logWriter print written lines as canary log messages
+type logWriter struct {
+	canary *canary.Canary
+	buffer bytes.Buffer
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.buffer.Write(p)
+	for {
+		line, err := w.buffer.ReadString('\n')
+		if err != nil {
+			// Keep incomplete line for next write
+			w.buffer.Reset()
+			w.buffer.WriteString(line)
+			return len(p), nil
+		}
+		w.canary.Logf("%s", strings.TrimRight(line, "\r\n"))
+	}
+}
+
+// Flush print remaining incomplete line
+func (w *logWriter) Flush() {
+	if w.buffer.Len() > 0 {
+		w.canary.Logf("%s", w.buffer.String())
+		w.buffer.Reset()
+	}
+}
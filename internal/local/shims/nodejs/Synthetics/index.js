@@ -0,0 +1,145 @@
+// Local shim of Synthetics runtime Synthetics module
+const fs = require('fs')
+const http = require('http')
+const https = require('https')
+const path = require('path')
+const log = require('SyntheticsLogger')
+
+const artifactsDir = process.env.CANARY_ARTIFACTS_DIR
+const steps = []
+let browser = null
+let page = null
+
+const requireFromCode = (name) => {
+  try {
+    return require(require.resolve(name, { paths: [process.env.CANARY_SRC, process.cwd()] }))
+  } catch (err) {
+    return null
+  }
+}
+
+const configuration = {
+  setConfig: () => {},
+  disableStepScreenshots: () => {},
+  enableStepScreenshots: () => {},
+  disableRequestMetrics: () => {},
+  enableRequestMetrics: () => {},
+  withIncludeRequestHeaders: () => configuration,
+  withIncludeResponseHeaders: () => configuration,
+  withIncludeRequestBody: () => configuration,
+  withIncludeResponseBody: () => configuration
+}
+
+const launch = async (options) => {
+  const puppeteer = requireFromCode('puppeteer') || requireFromCode('puppeteer-core')
+  if (!puppeteer) {
+    throw new Error('getPage requires puppeteer to be installed locally, run: npm install puppeteer')
+  }
+  browser = await puppeteer.launch(Object.assign({ headless: true }, options))
+  return browser
+}
+
+const getPage = async () => {
+  if (page) {
+    return page
+  }
+  if (!browser) {
+    await launch()
+  }
+  page = await browser.newPage()
+  return page
+}
+
+const takeScreenshot = async (stepName, suffix) => {
+  if (!page) {
+    return null
+  }
+  const fileName = `${String(steps.length).padStart(2, '0')}-${stepName}${suffix ? '-' + suffix : ''}.png`
+  await page.screenshot({ path: path.join(artifactsDir, fileName) })
+  const current = steps[steps.length - 1]
+  if (current && current.status === 'RUNNING') {
+    current.screenshots.push({ fileName, pageUrl: page.url() })
+  }
+  log.info(`Screenshot saved ${fileName}`)
+  return fileName
+}
+
+const runStep = async (stepName, fn, details) => {
+  const step = Object.assign({
+    stepName,
+    status: 'RUNNING',
+    startTime: new Date().toISOString(),
+    screenshots: []
+  }, details)
+  steps.push(step)
+  log.info(`Executing step: ${stepName}`)
+  try {
+    const result = await fn(step)
+    step.status = 'succeeded'
+    log.info(`Step ${stepName} passed`)
+    return result
+  } catch (err) {
+    step.status = 'failed'
+    step.failureReason = err && err.message ? err.message : String(err)
+    log.error(`Step ${stepName} failed: ${step.failureReason}`)
+    throw err
+  } finally {
+    step.endTime = new Date().toISOString()
+  }
+}
+
+const executeStep = async (stepName, fn) => {
+  return runStep(stepName, async () => {
+    const result = await fn()
+    await takeScreenshot(stepName, 'succeeded').catch(() => {})
+    return result
+  })
+}
+
+const executeHttpStep = async (stepName, requestOptions, callback) => {
+  return runStep(stepName, (step) => new Promise((resolve, reject) => {
+    const client = requestOptions.protocol === 'http:' ? http : https
+    const options = Object.assign({}, requestOptions)
+    delete options.body
+    step.request = {
+      method: options.method || 'GET',
+      url: `${options.protocol || 'https:'}//${options.hostname || options.host}${options.path || '/'}`,
+      headers: options.headers || {}
+    }
+    const req = client.request(options, (res) => {
+      step.response = {
+        statusCode: res.statusCode,
+        statusText: res.statusMessage,
+        headers: res.headers
+      }
+      Promise.resolve(callback ? callback(res) : null).then(resolve, reject)
+    })
+    req.on('error', reject)
+    if (requestOptions.body) {
+      req.write(requestOptions.body)
+    }
+    req.end()
+  }))
+}
+
+const close = async () => {
+  if (browser) {
+    await browser.close()
+  }
+  browser = null
+  page = null
+}
+
+const getSteps = () => steps
+
+module.exports = {
+  addUserAgent: async (page, userAgent) => page.setUserAgent(userAgent),
+  close,
+  executeHttpStep,
+  executeStep,
+  getConfiguration: () => configuration,
+  getPage,
+  getSteps,
+  launch,
+  takeScreenshot
+}
@@ -0,0 +1,25 @@
+// Local shim of Synthetics runtime SyntheticsLogger module
+const fs = require('fs')
+const path = require('path')
+
+const logFile = path.join(process.env.CANARY_ARTIFACTS_DIR, 'log.txt')
+
+const write = (level, args) => {
+  const message = args.map(arg => {
+    if (arg instanceof Error) {
+      return arg.stack || arg.message
+    }
+    return typeof arg === 'string' ? arg : JSON.stringify(arg)
+  }).join(' ')
+  const line = `${new Date().toISOString()} ${level}: ${message}`
+  console.log(line)
+  fs.appendFileSync(logFile, line + '\n')
+}
+
+module.exports = {
+  log: (...args) => write('INFO', args),
+  debug: (...args) => write('DEBUG', args),
+  info: (...args) => write('INFO', args),
+  warn: (...args) => write('WARN', args),
+  error: (...args) => write('ERROR', args)
+}
@@ -0,0 +1,45 @@
+// Local runner for Synthetics canaries handlers
+const fs = require('fs')
+const path = require('path')
+const synthetics = require('Synthetics')
+const log = require('SyntheticsLogger')
+
+const run = async () => {
+  const handler = process.env.CANARY_HANDLER
+  const separator = handler.lastIndexOf('.')
+  const modulePath = path.resolve(process.env.CANARY_SRC, handler.substring(0, separator))
+  const functionName = handler.substring(separator + 1)
+
+  const report = {
+    canaryName: process.env.CANARY_NAME,
+    canaryRunId: process.env.CANARY_RUN_ID,
+    artifactLocation: process.env.CANARY_ARTIFACTS_DIR,
+    runtimeVersion: process.env.CANARY_RUNTIME,
+    startTime: new Date().toISOString(),
+    status: 'PASSED'
+  }
+
+  try {
+    const result = await require(modulePath)[functionName]()
+    log.info(`Canary result: ${JSON.stringify(result)}`)
+  } catch (err) {
+    report.status = 'FAILED'
+    report.failureReason = err && err.message ? err.message : String(err)
+    log.error('Canary failed:', err)
+  }
+
+  await synthetics.close().catch(() => {})
+
+  report.endTime = new Date().toISOString()
+  report.customerScript = {
+    status: report.status,
+    startTime: report.startTime,
+    endTime: report.endTime,
+    steps: synthetics.getSteps()
+  }
+  fs.writeFileSync(path.join(process.env.CANARY_ARTIFACTS_DIR, `SyntheticsReport-${report.status}.json`), JSON.stringify(report, null, 2))
+
+  process.exit(report.status === 'PASSED' ? 0 : 1)
+}
+
+run()
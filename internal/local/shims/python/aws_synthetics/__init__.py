@@ -0,0 +1 @@
+# Local shim of Synthetics runtime aws_synthetics package
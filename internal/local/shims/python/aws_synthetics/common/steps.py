@@ -0,0 +1,43 @@
+# Steps recorded during a local run
+import datetime
+import os
+
+steps = []
+
+
+def _now():
+    return datetime.datetime.utcnow().isoformat() + "Z"
+
+
+def run_step(step_name, function_to_execute, screenshot=None):
+    from aws_synthetics.common import synthetics_logger as logger
+
+    step = {
+        "stepName": step_name,
+        "status": "RUNNING",
+        "startTime": _now(),
+        "screenshots": [],
+    }
+    steps.append(step)
+    logger.info("Executing step: %s" % step_name)
+    try:
+        result = function_to_execute()
+        step["status"] = "succeeded"
+        logger.info("Step %s passed" % step_name)
+        if screenshot is not None:
+            screenshot(step, "succeeded")
+        return result
+    except Exception as err:
+        step["status"] = "failed"
+        step["failureReason"] = str(err)
+        logger.error("Step %s failed: %s" % (step_name, err))
+        if screenshot is not None:
+            screenshot(step, "failed")
+        raise
+    finally:
+        step["endTime"] = _now()
+
+
+def screenshot_path(step_name, suffix):
+    file_name = "%02d-%s-%s.png" % (len(steps), step_name, suffix)
+    return file_name, os.path.join(os.environ["CANARY_ARTIFACTS_DIR"], file_name)
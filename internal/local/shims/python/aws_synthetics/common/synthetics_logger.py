@@ -0,0 +1,38 @@
+# Local shim of Synthetics runtime logger
+import datetime
+import os
+
+_log_file = os.path.join(os.environ["CANARY_ARTIFACTS_DIR"], "log.txt")
+
+
+def _write(level, message, *args):
+    if args:
+        message = message % args
+    line = "%s %s: %s" % (datetime.datetime.utcnow().isoformat() + "Z", level, message)
+    print(line, flush=True)
+    with open(_log_file, "a") as log_file:
+        log_file.write(line + "\n")
+
+
+def debug(message, *args):
+    _write("DEBUG", message, *args)
+
+
+def info(message, *args):
+    _write("INFO", message, *args)
+
+
+def warn(message, *args):
+    _write("WARN", message, *args)
+
+
+def warning(message, *args):
+    _write("WARN", message, *args)
+
+
+def error(message, *args):
+    _write("ERROR", message, *args)
+
+
+def exception(message, *args):
+    _write("ERROR", message, *args)
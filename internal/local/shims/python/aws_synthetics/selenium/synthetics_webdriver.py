@@ -0,0 +1,55 @@
+# Local shim of Synthetics runtime selenium webdriver
+import os
+
+from aws_synthetics.common import steps
+from aws_synthetics.common import synthetics_logger as logger
+
+_driver = None
+
+
+def Chrome(chrome_options=None, **kwargs):
+    global _driver
+    try:
+        from selenium import webdriver
+    except ImportError:
+        raise Exception("Chrome requires selenium to be installed locally, run: pip install selenium")
+
+    class LocalChrome(webdriver.Chrome):
+        def save_screenshot(self, filename):
+            path = os.path.join(os.environ["CANARY_ARTIFACTS_DIR"], os.path.basename(filename))
+            logger.info("Screenshot saved %s" % os.path.basename(filename))
+            if steps.steps and steps.steps[-1]["status"] == "RUNNING":
+                steps.steps[-1]["screenshots"].append({"fileName": os.path.basename(filename), "pageUrl": self.current_url})
+            return super(LocalChrome, self).save_screenshot(path)
+
+    if chrome_options is None:
+        chrome_options = webdriver.ChromeOptions()
+        chrome_options.add_argument("--headless")
+    _driver = LocalChrome(options=chrome_options, **kwargs)
+    return _driver
+
+
+def add_user_agent(user_agent_str):
+    pass
+
+
+def get_driver():
+    return _driver
+
+
+def execute_step(step_name, function_to_execute):
+    def screenshot(step, suffix):
+        if _driver is None:
+            return
+        file_name, path = steps.screenshot_path(step_name, suffix)
+        _driver.get_screenshot_as_file(path)
+        step["screenshots"].append({"fileName": file_name, "pageUrl": _driver.current_url})
+
+    return steps.run_step(step_name, function_to_execute, screenshot)
+
+
+def close():
+    global _driver
+    if _driver is not None:
+        _driver.quit()
+    _driver = None
@@ -0,0 +1,59 @@
+# Local runner for Synthetics canaries handlers
+import datetime
+import importlib
+import json
+import os
+import sys
+import traceback
+
+from aws_synthetics.common import steps
+from aws_synthetics.common import synthetics_logger as logger
+from aws_synthetics.selenium import synthetics_webdriver
+
+
+def now():
+    return datetime.datetime.utcnow().isoformat() + "Z"
+
+
+def run():
+    module_name, function_name = os.environ["CANARY_HANDLER"].rsplit(".", 1)
+    sys.path.insert(0, os.environ["CANARY_SRC"])
+
+    report = {
+        "canaryName": os.environ["CANARY_NAME"],
+        "canaryRunId": os.environ["CANARY_RUN_ID"],
+        "artifactLocation": os.environ["CANARY_ARTIFACTS_DIR"],
+        "runtimeVersion": os.environ["CANARY_RUNTIME"],
+        "startTime": now(),
+        "status": "PASSED",
+    }
+
+    try:
+        handler = getattr(importlib.import_module(module_name.replace("/", ".")), function_name)
+        result = handler({}, None)
+        logger.info("Canary result: %s" % json.dumps(result))
+    except Exception as err:
+        report["status"] = "FAILED"
+        report["failureReason"] = str(err)
+        logger.error("Canary failed: %s" % traceback.format_exc())
+
+    try:
+        synthetics_webdriver.close()
+    except Exception:
+        pass
+
+    report["endTime"] = now()
+    report["customerScript"] = {
+        "status": report["status"],
+        "startTime": report["startTime"],
+        "endTime": report["endTime"],
+        "steps": steps.steps,
+    }
+    with open(os.path.join(os.environ["CANARY_ARTIFACTS_DIR"], "SyntheticsReport-%s.json" % report["status"]), "w") as report_file:
+        json.dump(report, report_file, indent=2)
+
+    sys.exit(0 if report["status"] == "PASSED" else 1)
+
+
+if __name__ == "__main__":
+    run()
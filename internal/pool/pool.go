@@ -0,0 +1,226 @@
+package pool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+)
+
+// Task statuses
+const (
+	StatusSucceeded   = "succeeded"
+	StatusFailed      = "failed"
+	StatusSkipped     = "skipped"
+	StatusInterrupted = "interrupted"
+)
+
+// SkipError is returned by jobs that did not need to do anything
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return e.Reason
+}
+
+// Skip return a new SkipError
+func Skip(format string, a ...interface{}) error {
+	return &SkipError{
+		Reason: fmt.Sprintf(format, a...),
+	}
+}
+
+// IsSkipped check if error was generated by Skip
+func IsSkipped(err error) bool {
+	var skipErr *SkipError
+	return errors.As(err, &skipErr)
+}
+
+// Job is the function executed for every canary
+type Job func(ctx context.Context, canary *canary.Canary) error
+
+// Task structure
+type Task struct {
+	output *bytes.Buffer
+	done   chan struct{}
+
+	Canary   *canary.Canary
+	Status   string
+	Err      error
+	Duration time.Duration
+}
+
+// Pool structure
+type Pool struct {
+	output io.Writer
+
+	Concurrency int
+	Timeout     time.Duration
+}
+
+// New creates a new worker pool, a concurrency less than 1 means no limit
+// and a timeout of 0 means that each job can run without time limit
+func New(concurrency int, timeout time.Duration) *Pool {
+	return &Pool{
+		output:      os.Stdout,
+		Concurrency: concurrency,
+		Timeout:     timeout,
+	}
+}
+
+// SetOutput set canaries logs and summary output
+func (p *Pool) SetOutput(output io.Writer) {
+	p.output = output
+}
+
+// Run execute job for every canary, canaries output is flushed in order.
+// When context is done running jobs are interrupted and pending ones are skipped
+func (p *Pool) Run(ctx context.Context, canaries []*canary.Canary, job Job) *Summary {
+	tasks := make([]*Task, len(canaries))
+
+	// Setup concurrency limit
+	concurrency := p.Concurrency
+	if concurrency < 1 || concurrency > len(canaries) {
+		concurrency = len(canaries)
+	}
+	slots := make(chan struct{}, concurrency)
+
+	// Prepare tasks
+	for i, cy := range canaries {
+		tasks[i] = &Task{
+			output: new(bytes.Buffer),
+			done:   make(chan struct{}),
+			Canary: cy,
+		}
+
+		// Print directly when there is nothing to interleave with
+		if len(canaries) == 1 {
+			cy.SetOutput(p.output)
+		} else {
+			cy.SetOutput(tasks[i].output)
+		}
+	}
+
+	// Start jobs in canaries order when a slot is available
+	go func() {
+		for _, task := range tasks {
+			slots <- struct{}{}
+
+			go func(task *Task) {
+				defer close(task.done)
+				defer func() { <-slots }()
+
+				// Skip job if operation was already interrupted
+				if ctx.Err() != nil {
+					task.Status = StatusSkipped
+					task.Err = Skip("interrupted before start")
+					return
+				}
+
+				// Setup job timeout
+				jobCtx, cancel := ctx, context.CancelFunc(func() {})
+				if p.Timeout > 0 {
+					jobCtx, cancel = context.WithTimeout(ctx, p.Timeout)
+				}
+				defer cancel()
+
+				start := time.Now()
+				task.Err = job(jobCtx, task.Canary)
+				task.Duration = time.Since(start)
+
+				// Elaborate task status
+				if task.Err == nil {
+					task.Status = StatusSucceeded
+				} else if IsSkipped(task.Err) {
+					task.Status = StatusSkipped
+				} else if jobCtx.Err() == context.DeadlineExceeded {
+					task.Status = StatusInterrupted
+					task.Err = fmt.Errorf("[%s] Timeout: operation did not complete in %s", task.Canary.Name, p.Timeout)
+				} else if jobCtx.Err() != nil {
+					task.Status = StatusInterrupted
+					task.Err = fmt.Errorf("[%s] Interrupted: %s", task.Canary.Name, task.Err)
+				} else {
+					task.Status = StatusFailed
+				}
+			}(task)
+		}
+	}()
+
+	// Flush output in canaries order
+	for _, task := range tasks {
+		<-task.done
+		task.output.WriteTo(p.output)
+		task.Canary.SetOutput(p.output)
+	}
+
+	return &Summary{
+		output: p.output,
+		Tasks:  tasks,
+	}
+}
+
+// Summary structure
+type Summary struct {
+	output io.Writer
+
+	Tasks []*Task
+}
+
+// Count return the number of tasks in the provided statuses
+func (s *Summary) Count(statuses ...string) int {
+	count := 0
+	for _, task := range s.Tasks {
+		for _, status := range statuses {
+			if task.Status == status {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Print write summary table, for a single task only the error is printed
+func (s *Summary) Print() {
+	if len(s.Tasks) == 1 {
+		if s.Tasks[0].Status == StatusFailed || s.Tasks[0].Status == StatusInterrupted {
+			fmt.Fprintln(s.output, s.Tasks[0].Err)
+		}
+		s.printInterrupted()
+		return
+	}
+
+	round, _ := time.ParseDuration("10ms")
+
+	fmt.Fprintln(s.output, "")
+	fmt.Fprintln(s.output, fmt.Sprintf("%-25s\t%-10s\t%-10s\t%s", "Name", "Status", "Duration", "Reason"))
+	for _, task := range s.Tasks {
+		reason := ""
+		if task.Err != nil {
+			reason = task.Err.Error()
+		}
+		fmt.Fprintln(s.output, fmt.Sprintf("%-25s\t%-10s\t%-10s\t%s", task.Canary.Name, task.Status, task.Duration.Round(round), reason))
+	}
+	fmt.Fprintln(s.output, fmt.Sprintf("\n%d succeeded, %d failed, %d skipped, %d interrupted", s.Count(StatusSucceeded), s.Count(StatusFailed), s.Count(StatusSkipped), s.Count(StatusInterrupted)))
+	s.printInterrupted()
+}
+
+func (s *Summary) printInterrupted() {
+	names := []string{}
+	for _, task := range s.Tasks {
+		if task.Status == StatusInterrupted {
+			names = append(names, task.Canary.Name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	fmt.Fprintln(s.output, fmt.Sprintf("\nOperation interrupted while in progress, these canaries could be left mid-update: %s", strings.Join(names, ", ")))
+}
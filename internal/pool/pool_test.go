@@ -0,0 +1,119 @@
+package pool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+)
+
+func newCanaries(names ...string) []*canary.Canary {
+	canaries := []*canary.Canary{}
+	for _, name := range names {
+		canaries = append(canaries, canary.New(&awsinternal.Clients{}, name))
+	}
+	return canaries
+}
+
+func TestRunOutputOrder(t *testing.T) {
+	output := new(bytes.Buffer)
+	p := New(0, 0)
+	p.output = output
+
+	// First canary ends last, output must be flushed in canaries order anyway
+	delays := map[string]time.Duration{"a": 60 * time.Millisecond, "b": 30 * time.Millisecond, "c": 0}
+	summary := p.Run(context.Background(), newCanaries("a", "b", "c"), func(ctx context.Context, c *canary.Canary) error {
+		time.Sleep(delays[c.Name])
+		c.Logf("done")
+		return nil
+	})
+
+	expected := "[a] done\n[b] done\n[c] done\n"
+	if output.String() != expected {
+		t.Errorf("expected output %q, found %q", expected, output.String())
+	}
+	if count := summary.Count(StatusSucceeded); count != 3 {
+		t.Errorf("expected 3 succeeded, found %d", count)
+	}
+}
+
+func TestRunStatuses(t *testing.T) {
+	p := New(0, 50*time.Millisecond)
+	p.output = new(bytes.Buffer)
+
+	summary := p.Run(context.Background(), newCanaries("ok", "fail", "skip", "slow"), func(ctx context.Context, c *canary.Canary) error {
+		switch c.Name {
+		case "fail":
+			return errors.New("boom")
+		case "skip":
+			return Skip("nothing to do")
+		case "slow":
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	expected := map[string]string{
+		"ok":   StatusSucceeded,
+		"fail": StatusFailed,
+		"skip": StatusSkipped,
+		"slow": StatusInterrupted,
+	}
+	for _, task := range summary.Tasks {
+		if task.Status != expected[task.Canary.Name] {
+			t.Errorf("%s: expected status %s, found %s", task.Canary.Name, expected[task.Canary.Name], task.Status)
+		}
+	}
+	if err := summary.Tasks[3].Err; err == nil || strings.Contains(err.Error(), "Timeout") == false {
+		t.Errorf("expected timeout error, found %v", err)
+	}
+}
+
+func TestRunConcurrencyLimit(t *testing.T) {
+	p := New(2, 0)
+	p.output = new(bytes.Buffer)
+
+	var running, maxRunning int32
+	p.Run(context.Background(), newCanaries("a", "b", "c", "d", "e"), func(ctx context.Context, c *canary.Canary) error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+
+	if maxRunning != 2 {
+		t.Errorf("expected at most 2 jobs in parallel, found %d", maxRunning)
+	}
+}
+
+func TestRunInterruptedSkipsPending(t *testing.T) {
+	p := New(1, 0)
+	p.output = new(bytes.Buffer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	summary := p.Run(ctx, newCanaries("a", "b"), func(ctx context.Context, c *canary.Canary) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if status := summary.Tasks[0].Status; status != StatusInterrupted {
+		t.Errorf("expected first task interrupted, found %s", status)
+	}
+	if status := summary.Tasks[1].Status; status != StatusSkipped {
+		t.Errorf("expected pending task skipped, found %s", status)
+	}
+}
@@ -0,0 +1,276 @@
+package testreport
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+)
+
+// Result statuses
+const (
+	StatusPassed  = "PASSED"
+	StatusFailed  = "FAILED"
+	StatusError   = "ERROR"
+	StatusSkipped = "SKIPPED"
+)
+
+// Result structure, the outcome of a single canary test run
+type Result struct {
+	Canary   string         `json:"canary"`
+	RunID    string         `json:"runId,omitempty"`
+	Status   string         `json:"status"`
+	Reason   string         `json:"reason,omitempty"`
+	Duration time.Duration  `json:"-"`
+	Report   *canary.Report `json:"-"`
+}
+
+// Count return the number of results in the provided status
+func Count(results []*Result, status string) int {
+	count := 0
+	for _, result := range results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Write render results in the provided format: junit, tap or json
+func Write(output io.Writer, format string, results []*Result) error {
+	switch format {
+	case "junit":
+		return WriteJUnit(output, results)
+	case "tap":
+		return WriteTAP(output, results)
+	case "json":
+		return WriteJSON(output, results)
+	}
+	return fmt.Errorf("Report format %s not supported, valid formats are junit, tap and json", format)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     float64           `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     float64          `xml:"time,attr"`
+	ID       string           `xml:"id,attr,omitempty"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// WriteJUnit render results as JUnit XML, a test suite for each canary and a test case for each step
+func WriteJUnit(output io.Writer, results []*Result) error {
+	suites := &junitTestSuites{
+		Name: "aws-canary",
+	}
+
+	for _, result := range results {
+		suite := &junitTestSuite{
+			Name: result.Canary,
+			ID:   result.RunID,
+			Time: result.Duration.Seconds(),
+		}
+
+		for _, testCase := range junitTestCases(result) {
+			suite.Tests++
+			if testCase.Failure != nil {
+				suite.Failures++
+			}
+			if testCase.Error != nil {
+				suite.Errors++
+			}
+			if testCase.Skipped != nil {
+				suite.Skipped++
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+		suites.Time += suite.Time
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	_, err := io.WriteString(output, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(output)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(suites)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(output, "\n")
+	return err
+}
+
+func junitTestCases(result *Result) []*junitTestCase {
+	// Without steps the whole run is a single test case
+	if result.Report == nil || len(result.Report.GetSteps()) == 0 {
+		testCase := &junitTestCase{
+			Name:      result.Canary,
+			ClassName: result.Canary,
+			Time:      result.Duration.Seconds(),
+		}
+		switch result.Status {
+		case StatusFailed:
+			testCase.Failure = &junitMessage{Message: result.Reason, Content: result.Reason}
+		case StatusError:
+			testCase.Error = &junitMessage{Message: result.Reason, Content: result.Reason}
+		case StatusSkipped:
+			testCase.Skipped = &junitMessage{Message: result.Reason}
+		}
+		return []*junitTestCase{testCase}
+	}
+
+	testCases := []*junitTestCase{}
+	stepFailed := false
+	for _, step := range result.Report.GetSteps() {
+		testCase := &junitTestCase{
+			Name:      step.Name,
+			ClassName: result.Canary,
+			Time:      step.Duration().Seconds(),
+		}
+		if step.IsPassed() == false {
+			testCase.Failure = &junitMessage{Message: step.FailureReason, Content: step.FailureReason}
+			stepFailed = true
+		}
+		testCases = append(testCases, testCase)
+	}
+
+	// Report run failures not related to a step
+	if result.Status == StatusFailed && stepFailed == false {
+		testCases = append(testCases, &junitTestCase{
+			Name:      result.Canary,
+			ClassName: result.Canary,
+			Failure:   &junitMessage{Message: result.Reason, Content: result.Reason},
+		})
+	}
+	if result.Status == StatusError {
+		testCases = append(testCases, &junitTestCase{
+			Name:      result.Canary,
+			ClassName: result.Canary,
+			Error:     &junitMessage{Message: result.Reason, Content: result.Reason},
+		})
+	}
+
+	return testCases
+}
+
+// WriteTAP render results as TAP version 13, a test point for each canary and a sub test for each step
+func WriteTAP(output io.Writer, results []*Result) error {
+	lines := []string{
+		"TAP version 13",
+		fmt.Sprintf("1..%d", len(results)),
+	}
+
+	for i, result := range results {
+		// Add steps as indented sub tests
+		if result.Report != nil && len(result.Report.GetSteps()) > 0 {
+			lines = append(lines, fmt.Sprintf("    1..%d", len(result.Report.GetSteps())))
+			for j, step := range result.Report.GetSteps() {
+				lines = append(lines, "    "+tapTestPoint(j+1, step.Name, step.IsPassed(), ""))
+				if step.IsPassed() == false {
+					lines = append(lines, tapDiagnostic("    ", step.Status, step.FailureReason)...)
+				}
+			}
+		}
+
+		// Add canary test point
+		directive := ""
+		if result.Status == StatusSkipped {
+			directive = " # SKIP " + result.Reason
+		}
+		lines = append(lines, tapTestPoint(i+1, result.Canary, result.Status == StatusPassed || result.Status == StatusSkipped, directive))
+		if result.Status == StatusFailed || result.Status == StatusError {
+			lines = append(lines, tapDiagnostic("", result.Status, result.Reason)...)
+		}
+	}
+
+	_, err := io.WriteString(output, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func tapTestPoint(number int, description string, ok bool, directive string) string {
+	status := "ok"
+	if ok == false {
+		status = "not ok"
+	}
+	return fmt.Sprintf("%s %d - %s%s", status, number, strings.ReplaceAll(description, "#", "\\#"), directive)
+}
+
+func tapDiagnostic(indent string, status string, reason string) []string {
+	return []string{
+		indent + "  ---",
+		indent + fmt.Sprintf("  status: %s", status),
+		indent + fmt.Sprintf("  message: %q", reason),
+		indent + "  ...",
+	}
+}
+
+// WriteJSON render results as JSON
+func WriteJSON(output io.Writer, results []*Result) error {
+	type jsonResult struct {
+		*Result
+		Duration float64        `json:"duration"`
+		Steps    []*canary.Step `json:"steps"`
+	}
+
+	jsonResults := []*jsonResult{}
+	for _, result := range results {
+		steps := []*canary.Step{}
+		if result.Report != nil {
+			steps = result.Report.GetSteps()
+		}
+		jsonResults = append(jsonResults, &jsonResult{
+			Result:   result,
+			Duration: result.Duration.Seconds(),
+			Steps:    steps,
+		})
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]interface{}{
+		"tests":   len(results),
+		"passed":  Count(results, StatusPassed),
+		"failed":  Count(results, StatusFailed),
+		"errors":  Count(results, StatusError),
+		"skipped": Count(results, StatusSkipped),
+		"results": jsonResults,
+	})
+}
@@ -0,0 +1,188 @@
+package testreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daaru00/aws-canary-cli/internal/canary"
+)
+
+func newResults() []*Result {
+	start := time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)
+	return []*Result{
+		{
+			Canary:   "web",
+			RunID:    "run-1",
+			Status:   StatusFailed,
+			Reason:   "Step checkout failed",
+			Duration: 12 * time.Second,
+			Report: &canary.Report{
+				CustomerScript: canary.CustomerScript{
+					Steps: []*canary.Step{
+						{Name: "home", Status: "succeeded", StartTime: start, EndTime: start.Add(1500 * time.Millisecond)},
+						{Name: "checkout", Status: "failed", FailureReason: "selector #buy not found", StartTime: start, EndTime: start.Add(2 * time.Second)},
+					},
+				},
+			},
+		},
+		{
+			Canary:   "api",
+			Status:   StatusPassed,
+			Duration: 3 * time.Second,
+		},
+		{
+			Canary: "broken",
+			Status: StatusError,
+			Reason: "not yet deployed",
+		},
+		{
+			Canary: "running",
+			Status: StatusSkipped,
+			Reason: "already running",
+		},
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	output := new(bytes.Buffer)
+	err := Write(output, "junit", newResults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="aws-canary" tests="5" failures="1" errors="1" skipped="1" time="15">
+  <testsuite name="web" tests="2" failures="1" errors="0" skipped="0" time="12" id="run-1">
+    <testcase name="home" classname="web" time="1.5"></testcase>
+    <testcase name="checkout" classname="web" time="2">
+      <failure message="selector #buy not found">selector #buy not found</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="api" tests="1" failures="0" errors="0" skipped="0" time="3">
+    <testcase name="api" classname="api" time="3"></testcase>
+  </testsuite>
+  <testsuite name="broken" tests="1" failures="0" errors="1" skipped="0" time="0">
+    <testcase name="broken" classname="broken" time="0">
+      <error message="not yet deployed">not yet deployed</error>
+    </testcase>
+  </testsuite>
+  <testsuite name="running" tests="1" failures="0" errors="0" skipped="1" time="0">
+    <testcase name="running" classname="running" time="0">
+      <skipped message="already running"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if output.String() != expected {
+		t.Errorf("expected:\n%s\nfound:\n%s", expected, output.String())
+	}
+}
+
+func TestWriteJUnitRunFailureWithoutFailedStep(t *testing.T) {
+	results := []*Result{
+		{
+			Canary: "web",
+			Status: StatusFailed,
+			Reason: "Runtime error",
+			Report: &canary.Report{
+				CustomerScript: canary.CustomerScript{
+					Steps: []*canary.Step{{Name: "home", Status: "succeeded"}},
+				},
+			},
+		},
+	}
+
+	output := new(bytes.Buffer)
+	err := WriteJUnit(output, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output.String(), `tests="2" failures="1"`) == false {
+		t.Errorf("expected run failure reported as an additional test case, found:\n%s", output.String())
+	}
+}
+
+func TestWriteTAP(t *testing.T) {
+	output := new(bytes.Buffer)
+	err := Write(output, "tap", newResults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `TAP version 13
+1..4
+    1..2
+    ok 1 - home
+    not ok 2 - checkout
+      ---
+      status: failed
+      message: "selector #buy not found"
+      ...
+not ok 1 - web
+  ---
+  status: FAILED
+  message: "Step checkout failed"
+  ...
+ok 2 - api
+not ok 3 - broken
+  ---
+  status: ERROR
+  message: "not yet deployed"
+  ...
+ok 4 - running # SKIP already running
+`
+	if output.String() != expected {
+		t.Errorf("expected:\n%s\nfound:\n%s", expected, output.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	output := new(bytes.Buffer)
+	err := Write(output, "json", newResults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := struct {
+		Tests   int `json:"tests"`
+		Passed  int `json:"passed"`
+		Failed  int `json:"failed"`
+		Errors  int `json:"errors"`
+		Skipped int `json:"skipped"`
+		Results []struct {
+			Canary   string         `json:"canary"`
+			RunID    string         `json:"runId"`
+			Status   string         `json:"status"`
+			Duration float64        `json:"duration"`
+			Steps    []*canary.Step `json:"steps"`
+		} `json:"results"`
+	}{}
+	err = json.Unmarshal(output.Bytes(), &parsed)
+	if err != nil {
+		t.Fatalf("invalid JSON: %s\n%s", err, output.String())
+	}
+
+	if parsed.Tests != 4 || parsed.Passed != 1 || parsed.Failed != 1 || parsed.Errors != 1 || parsed.Skipped != 1 {
+		t.Errorf("unexpected counters: %+v", parsed)
+	}
+	if len(parsed.Results) != 4 {
+		t.Fatalf("expected 4 results, found %d", len(parsed.Results))
+	}
+	web := parsed.Results[0]
+	if web.Canary != "web" || web.RunID != "run-1" || web.Status != StatusFailed || web.Duration != 12 || len(web.Steps) != 2 {
+		t.Errorf("unexpected web result: %+v", web)
+	}
+	if parsed.Results[1].Steps == nil || len(parsed.Results[1].Steps) != 0 {
+		t.Errorf("expected empty steps list for results without report")
+	}
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	err := Write(new(bytes.Buffer), "html", newResults())
+	if err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
@@ -0,0 +1,215 @@
+package topic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	awsinternal "github.com/daaru00/aws-canary-cli/internal/aws"
+)
+
+// Supported subscriptions protocols
+var protocols = map[string]bool{
+	"email":      true,
+	"email-json": true,
+	"http":       true,
+	"https":      true,
+	"lambda":     true,
+	"sqs":        true,
+	"sms":        true,
+}
+
+// Subscription configuration
+type Subscription struct {
+	Protocol string `yaml:"protocol" json:"protocol"`
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+}
+
+// Config configuration
+type Config struct {
+	Project       string         `yaml:"project" json:"project"`
+	KmsKey        string         `yaml:"kmsKey" json:"kmsKey"`
+	Subscriptions []Subscription `yaml:"subscriptions" json:"subscriptions"`
+}
+
+// Validate check notifications configuration
+func (c *Config) Validate() error {
+	for _, subscription := range c.Subscriptions {
+		if protocols[subscription.Protocol] == false {
+			return fmt.Errorf("Subscription protocol %s not supported, use email, https, http, lambda, sqs or sms", subscription.Protocol)
+		}
+		if len(subscription.Endpoint) == 0 {
+			return fmt.Errorf("Subscription endpoint is required for protocol %s", subscription.Protocol)
+		}
+	}
+	return nil
+}
+
+// Topic structure
+type Topic struct {
+	clients *awsinternal.Clients
+
+	Name   *string
+	Arn    *string
+	KmsKey string
+	Tags   map[string]string
+}
+
+// New creates a new Topic
+func New(clients *awsinternal.Clients, name *string, region *string, accountID *string) *Topic {
+	arn := fmt.Sprintf("arn:aws:sns:%s:%s:%s", *region, *accountID, *name)
+
+	return &Topic{
+		clients: clients,
+		Name:    name,
+		Arn:     &arn,
+	}
+}
+
+// IsDeployed check if SNS Topic is present in current AWS account
+func (t *Topic) IsDeployed(ctx context.Context) bool {
+	_, err := t.clients.SNS.GetTopicAttributesWithContext(ctx, &sns.GetTopicAttributesInput{
+		TopicArn: t.Arn,
+	})
+	return err == nil
+}
+
+// Deploy Topic
+func (t *Topic) Deploy(ctx context.Context) error {
+
+	// Check if topic is already deployed
+	if t.IsDeployed(ctx) == false {
+		input := &sns.CreateTopicInput{
+			Name: t.Name,
+		}
+		for key, value := range t.Tags {
+			input.Tags = append(input.Tags, &sns.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			})
+		}
+		if len(t.KmsKey) > 0 {
+			input.Attributes = map[string]*string{
+				"KmsMasterKeyId": aws.String(t.KmsKey),
+			}
+		}
+
+		// Create new Topic
+		res, err := t.clients.SNS.CreateTopicWithContext(ctx, input)
+		if err != nil {
+			return err
+		}
+		t.Arn = res.TopicArn
+		return nil
+	}
+
+	// Update encryption, an empty key disable it
+	_, err := t.clients.SNS.SetTopicAttributesWithContext(ctx, &sns.SetTopicAttributesInput{
+		TopicArn:       t.Arn,
+		AttributeName:  aws.String("KmsMasterKeyId"),
+		AttributeValue: aws.String(t.KmsKey),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Update tags, other tags are left untouched
+	if len(t.Tags) == 0 {
+		return nil
+	}
+	input := &sns.TagResourceInput{
+		ResourceArn: t.Arn,
+	}
+	for key, value := range t.Tags {
+		input.Tags = append(input.Tags, &sns.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+	_, err = t.clients.SNS.TagResourceWithContext(ctx, input)
+
+	return err
+}
+
+// DeploySubscriptions subscribe missing endpoints, when exclusive is true
+// confirmed subscriptions not in the list are removed
+func (t *Topic) DeploySubscriptions(ctx context.Context, subscriptions []Subscription, exclusive bool) error {
+	current, err := t.listSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Add missing subscriptions
+	desired := map[string]bool{}
+	for _, subscription := range subscriptions {
+		key := subscription.Protocol + ":" + subscription.Endpoint
+		desired[key] = true
+		if _, ok := current[key]; ok {
+			continue
+		}
+
+		_, err = t.clients.SNS.SubscribeWithContext(ctx, &sns.SubscribeInput{
+			TopicArn: t.Arn,
+			Protocol: aws.String(subscription.Protocol),
+			Endpoint: aws.String(subscription.Endpoint),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if exclusive == false {
+		return nil
+	}
+
+	// Remove subscriptions no longer in configuration
+	for key, subscriptionArn := range current {
+		// Pending subscriptions cannot be removed
+		if desired[key] || subscriptionArn == "PendingConfirmation" {
+			continue
+		}
+
+		_, err = t.clients.SNS.UnsubscribeWithContext(ctx, &sns.UnsubscribeInput{
+			SubscriptionArn: aws.String(subscriptionArn),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove Topic, subscriptions are deleted with it
+func (t *Topic) Remove(ctx context.Context) error {
+	_, err := t.clients.SNS.DeleteTopicWithContext(ctx, &sns.DeleteTopicInput{
+		TopicArn: t.Arn,
+	})
+	return err
+}
+
+// listSubscriptions return subscriptions ARNs by protocol and endpoint
+func (t *Topic) listSubscriptions(ctx context.Context) (map[string]string, error) {
+	subscriptions := map[string]string{}
+
+	var nextToken *string
+	for {
+		res, err := t.clients.SNS.ListSubscriptionsByTopicWithContext(ctx, &sns.ListSubscriptionsByTopicInput{
+			TopicArn:  t.Arn,
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return subscriptions, err
+		}
+
+		for _, subscription := range res.Subscriptions {
+			subscriptions[*subscription.Protocol+":"+*subscription.Endpoint] = *subscription.SubscriptionArn
+		}
+
+		if res.NextToken == nil {
+			return subscriptions, nil
+		}
+		nextToken = res.NextToken
+	}
+}
@@ -1,17 +1,33 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/daaru00/aws-canary-cli/cmd/artifacts"
 	"github.com/daaru00/aws-canary-cli/cmd/build"
+	"github.com/daaru00/aws-canary-cli/cmd/dashboard"
 	"github.com/daaru00/aws-canary-cli/cmd/deploy"
+	"github.com/daaru00/aws-canary-cli/cmd/drift"
+	"github.com/daaru00/aws-canary-cli/cmd/groups"
+	"github.com/daaru00/aws-canary-cli/cmd/history"
 	"github.com/daaru00/aws-canary-cli/cmd/logs"
+	"github.com/daaru00/aws-canary-cli/cmd/metrics"
+	"github.com/daaru00/aws-canary-cli/cmd/prune"
 	"github.com/daaru00/aws-canary-cli/cmd/remove"
 	"github.com/daaru00/aws-canary-cli/cmd/results"
+	"github.com/daaru00/aws-canary-cli/cmd/rollback"
+	"github.com/daaru00/aws-canary-cli/cmd/runlocal"
+	"github.com/daaru00/aws-canary-cli/cmd/runtimes"
 	"github.com/daaru00/aws-canary-cli/cmd/start"
 	"github.com/daaru00/aws-canary-cli/cmd/stop"
+	"github.com/daaru00/aws-canary-cli/cmd/test"
+	"github.com/daaru00/aws-canary-cli/cmd/upgraderuntime"
+	"github.com/daaru00/aws-canary-cli/cmd/visual"
 	"github.com/daaru00/aws-canary-cli/internal/config"
 	"github.com/urfave/cli/v2"
 )
@@ -53,6 +69,32 @@ func main() {
 			Value:   "yml",
 			EnvVars: []string{"CANARY_CONFIG_PARSER"},
 		},
+		&cli.StringFlag{
+			Name:    "project",
+			Usage:   "Project name, tagged on resources created by the CLI when not declared in canary configuration",
+			EnvVars: []string{"CANARY_PROJECT"},
+		},
+		&cli.IntFlag{
+			Name:    "max-retries",
+			Usage:   "Maximum number of retries for throttled or conflicting AWS requests",
+			Value:   6,
+			EnvVars: []string{"CANARY_MAX_RETRIES"},
+		},
+		&cli.StringFlag{
+			Name:    "endpoint-url",
+			Usage:   "Custom AWS endpoint URL for all services, for example a local emulator",
+			EnvVars: []string{"AWS_ENDPOINT_URL"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "service-endpoint-url",
+			Usage:   "Custom AWS endpoint URL for a single service in format service=url, valid services are synthetics, s3, iam, lambda, sts, cloudwatch, logs and sns",
+			EnvVars: []string{"CANARY_SERVICE_ENDPOINT_URLS"},
+		},
+		&cli.BoolFlag{
+			Name:    "s3-path-style",
+			Usage:   "Use S3 path-style addressing instead of virtual hosted-style",
+			EnvVars: []string{"AWS_S3_FORCE_PATH_STYLE", "CANARY_S3_PATH_STYLE"},
+		},
 	}
 
 	// Create CLI application
@@ -68,8 +110,21 @@ func main() {
 			remove.NewCommand(globalFlags),
 			start.NewCommand(globalFlags),
 			stop.NewCommand(globalFlags),
+			test.NewCommand(globalFlags),
 			logs.NewCommand(globalFlags),
 			results.NewCommand(globalFlags),
+			artifacts.NewCommand(globalFlags),
+			metrics.NewCommand(globalFlags),
+			dashboard.NewCommand(globalFlags),
+			groups.NewCommand(globalFlags),
+			visual.NewCommand(globalFlags),
+			runtimes.NewCommand(globalFlags),
+			upgraderuntime.NewCommand(globalFlags),
+			history.NewCommand(globalFlags),
+			rollback.NewCommand(globalFlags),
+			drift.NewCommand(globalFlags),
+			prune.NewCommand(globalFlags),
+			runlocal.NewCommand(globalFlags),
 		},
 		Flags:                globalFlags,
 		EnableBashCompletion: true,
@@ -90,8 +145,19 @@ func main() {
 		},
 	}
 
+	// Stop waiting operations on interrupt, a second interrupt will terminate immediately
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		signal.Stop(signals)
+		fmt.Println("\nInterrupted, stopping running operations and cleaning temporary files..")
+		cancel()
+	}()
+
 	// Run the CLI application
-	err = app.Run(os.Args)
+	err = app.RunContext(ctx, os.Args)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)